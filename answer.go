@@ -1,7 +1,7 @@
 package dnsPacket
 
 import (
-	"bytes"
+	"encoding/binary"
 	"fmt"
 )
 
@@ -27,6 +27,11 @@ import (
 +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
 */
 
+//Answer is a resource record. It is used for the answer, authority
+//and additional sections alike.
+//Decode fills in RData with the typed record (RecordTypeA, RecordTypeSRV...)
+//for every registered type and with a RecordTypeDefault for any other type.
+//Data is only used when encoding records that have no RData
 type Answer struct {
 	Name     string
 	Type     int
@@ -34,74 +39,176 @@ type Answer struct {
 	TTL      uint32
 	RdLength int
 	Data     []byte
+	RData    PacketProcessor
 }
 
+func newAnswer(name string, aclass int, atype int, ttl uint32, dataLength int, data []byte) Answer {
+	answer := Answer{
+		Name:     name,
+		Class:    aclass,
+		Type:     atype,
+		TTL:      ttl,
+		RdLength: dataLength,
+	}
+	answer.Data = make([]byte, len(data))
+	for i := range data {
+		answer.Data[i] = data[i]
+	}
+
+	return answer
+}
+
+//decodes the resource record starting at msg[off:] and returns it
+//together with how many bytes it occupies. Errors are *DecodeError
+func decodeAnswer(msg []byte, off int) (Answer, int, error) {
+	//the name may be written out in full, be a pointer to an earlier name
+	//or a mix of both
+	answerName, n, err := decodeQname(msg, off)
+	if err != nil {
+		return Answer{}, 0, &DecodeError{Offset: off, Err: err}
+	}
+	startOfAnswerType := off + n
+
+	//calculate bounds for answer parts (answerType,answerClass, TTL, dataLength and Data)
+	endOfAnswerType := startOfAnswerType + 2 //2 bytes for answerType
+	startOfAnswerClass := endOfAnswerType
+	endOfAnswerClass := startOfAnswerClass + 2 //2 bytes for answerClass
+	startOfTTL := endOfAnswerClass
+	endOfTTL := startOfTTL + 4 //4 bytes for TTL
+	startOfDataLength := endOfTTL
+	endOfDataLength := startOfDataLength + 2 //2bytes for dataLength
+	startOfData := endOfDataLength
+
+	if endOfDataLength > len(msg) {
+		return Answer{}, 0, &DecodeError{Offset: off, Err: ErrShortBuffer}
+	}
+
+	anType := decodePart(msg, startOfAnswerType, endOfAnswerType)
+	anClass := decodePart(msg, startOfAnswerClass, endOfAnswerClass)
+	ttl := binary.BigEndian.Uint32(msg[startOfTTL:endOfTTL])
+	dataLength := decodePart(msg, startOfDataLength, endOfDataLength)
+	endOfData := startOfData + int(dataLength)
+
+	if endOfData > len(msg) {
+		return Answer{}, 0, &DecodeError{Offset: startOfDataLength, Err: ErrBadRdLength}
+	}
+
+	answer := Answer{
+		Name:     answerName,
+		Class:    int(anClass),
+		Type:     int(anType),
+		TTL:      ttl,
+		RdLength: int(dataLength),
+	}
+
+	//types that are not registered are kept as opaque bytes
+	answer.RData = newRData(answer.Type)
+	if answer.RData == nil {
+		answer.RData = &RecordTypeDefault{Code: answer.Type}
+	}
+
+	if err := answer.RData.Decode(msg, startOfData, int(dataLength)); err != nil {
+		return Answer{}, 0, &DecodeError{Offset: startOfData, Err: err}
+	}
+
+	return answer, endOfData - off, nil
+}
+
+//OwnerName returns Name parsed as a Name, with its labels and length
+//checked as Encode checks them
+func (a Answer) OwnerName() (Name, error) {
+	return NewName(a.Name)
+}
+
+//String writes the record in presentation format, the way it appears in
+//zone files and dig output: name, TTL, class, type and the RDATA in the
+//textual form of its type
 func (a Answer) String() string {
-	buf := new(bytes.Buffer)
+	return fmt.Sprintf("%s\t%d\t%s\t%s\t%s", fqdn(a.Name), a.TTL, ClassToString(a.Class), TypeToString(a.Type), a.rdataString())
+}
 
-	buf.WriteString(fmt.Sprintf("Name: %s Type: %d Class: %d TTL: %d DataLength: %d\n", a.Name, a.Type, a.Class, a.TTL, a.RdLength))
-	buf.WriteString(fmt.Sprint("Data: ", a.Data))
+//UnicodeString works like String but writes the A-labels of the owner
+//and of the names in the RDATA in Unicode, see ToUnicode
+func (a Answer) UnicodeString() string {
+	a.Name = unicodeName(a.Name)
+	a.RData = unicodeRData(a.RData)
 
-	return buf.String()
+	return a.String()
 }
 
-//Processes an answer based on the type
-//and returns the correspnding type to the caller
-//for example if we process an 'A' record we get back
-//a RecrodTypeA which has a single property IPv4 address
-//But if we process a 'SRV' record we get back a RecordTypeSRV
-//which contains port,target etc.
-//all these types implement the PacketProcessor interface
-func (a *Answer) Process() PacketProcessor {
-	var p PacketProcessor
-
-	switch a.Type {
-	case DNSRecordTypeA:
-		p = &RecordTypeA{}
-
-	case DNSRecordTypeSRV:
-		p = &RecordTypeSRV{}
-
-	default:
-		p = &RecordTypeDefault{}
+//RDATA without a textual form, raw Data or a registered type without
+//a String method, is written in the generic form of RFC 3597
+func (a Answer) rdataString() string {
+	if a.RData == nil {
+		return (&RecordTypeDefault{Data: a.Data}).String()
 	}
 
-	p.Process(*a)
+	if stringer, ok := a.RData.(fmt.Stringer); ok {
+		return stringer.String()
+	}
 
-	return p
+	data, err := a.RData.Encode(make([]byte, 0), nil)
+	if err != nil {
+		return fmt.Sprintf("; %s", err)
+	}
 
+	return (&RecordTypeDefault{Data: data}).String()
 }
 
-func (a *Answer) Encode(offset int) []byte {
-	answer := make([]byte, 0)
-	//encode the name - should be compressed, but if packet contains 0 questions - we can't compress it
-	var name []byte
-	if offset != 0 {
-		compressed := OffsetMarker | offset
-		name, _ = fromIntToBytes(uint16(compressed))
-	} else { //do not compress the name
-		name = encodeQname(a.Name)
+//Encode appends the answer to msg, which must hold the message encoded so far.
+//The name is compressed against the names recorded in compression, pass a nil
+//map to write it in full.
+//The RDATA is written from RData when it is set and from Data otherwise,
+//RDLENGTH is always the length actually written. RData of another type
+//than the answer is an error wrapping ErrBadRdata
+func (a *Answer) Encode(msg []byte, compression map[string]int) ([]byte, error) {
+	if a.RData != nil && a.RData.Type() != a.Type {
+		return nil, fmt.Errorf("dnsPacket: %s record for %s holds %s rdata: %w", TypeToString(a.Type), a.Name, TypeToString(a.RData.Type()), ErrBadRdata)
 	}
 
 	aType, _ := fromIntToBytes(uint16(a.Type))
 	aClass, _ := fromIntToBytes(uint16(a.Class))
 	ttl, _ := fromUint32ToBytes(a.TTL)
-	rdLength, _ := fromIntToBytes(uint16(a.RdLength))
 
-	answer = append(answer, name...)
-	answer = append(answer, aType...)
-	answer = append(answer, aClass...)
-	answer = append(answer, ttl...)
-	answer = append(answer, rdLength...)
-	answer = append(answer, a.Data...)
+	msg, err := encodeName(msg, a.Name, compression, true)
+	if err != nil {
+		return nil, fmt.Errorf("dnsPacket: owner name %q: %w", a.Name, err)
+	}
 
-	return answer
+	msg = append(msg, aType...)
+	msg = append(msg, aClass...)
+	msg = append(msg, ttl...)
+
+	//rdlength is filled in once the rdata has been written
+	startOfDataLength := len(msg)
+	msg = append(msg, 0, 0)
+
+	if a.RData != nil {
+		msg, err = a.RData.Encode(msg, compression)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		msg = append(msg, a.Data...)
+	}
+
+	dataLength := len(msg) - startOfDataLength - 2
+	if dataLength > maxRdLength {
+		return nil, fmt.Errorf("dnsPacket: %d bytes of rdata for %s: %w", dataLength, a.Name, ErrRdataTooLong)
+	}
+	binary.BigEndian.PutUint16(msg[startOfDataLength:], uint16(dataLength))
+
+	return msg, nil
 }
 
 //PacketProcessor interface. All recordType's should
 //implement this interface
 type PacketProcessor interface {
-	Process(Answer)
-	Encode() []byte
+	//Decode reads the RDATA found at msg[off:off+length].
+	//msg is the whole message so compressed names can be followed
+	Decode(msg []byte, off int, length int) error
+	//Encode appends the RDATA to msg, which holds the message encoded so far.
+	//Names may be compressed against compression where the type allows it
+	Encode(msg []byte, compression map[string]int) ([]byte, error)
 	Type() int
 }
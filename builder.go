@@ -0,0 +1,134 @@
+package dnsPacket
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+)
+
+//Section names one of the three record sections of a packet
+type Section int
+
+const (
+	SectionAnswer Section = iota
+	SectionAuthority
+	SectionAdditional
+)
+
+//NewQuery returns a query for name and qtype in class IN with a random
+//ID and the RD flag set
+func NewQuery(name string, qtype int) *DNSPacket {
+	query := &DNSPacket{
+		Header: Header{ID: randomID(), RD: true},
+	}
+
+	query.AddQuestion(name, QclassIN, qtype)
+
+	return query
+}
+
+//NewIDNQuery works like NewQuery but name may hold Unicode labels,
+//which are converted to A-labels with ToASCII
+func NewIDNQuery(name string, qtype int) (*DNSPacket, error) {
+	ascii, err := ToASCII(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewQuery(ascii, qtype), nil
+}
+
+//Reply returns an empty response to the packet. The ID, opcode, RD and
+//CD flags and the questions are copied over
+func (dns *DNSPacket) Reply() *DNSPacket {
+	reply := &DNSPacket{
+		Header: Header{
+			ID:     dns.ID,
+			QR:     true,
+			Opcode: dns.Opcode,
+			RD:     dns.RD,
+			CD:     dns.CD,
+		},
+		Questions: append([]Question(nil), dns.Questions...),
+	}
+
+	reply.updateCounts()
+
+	return reply
+}
+
+//SetRcode marks the packet as a response with the given response code.
+//Codes above 15 are split with SetExtendedRcode, which adds EDNS if needed
+func (dns *DNSPacket) SetRcode(rcode Rcode) {
+	dns.QR = true
+	dns.SetExtendedRcode(rcode)
+	dns.updateCounts()
+}
+
+//SetNXDomain marks the packet as a NXDOMAIN response. If soa is not nil
+//it is added to the authority section, owned by zone, so the response can
+//be cached (RFC 2308). Its TTL is the MINIMUM field
+func (dns *DNSPacket) SetNXDomain(zone string, soa *RecordTypeSOA) {
+	dns.SetRcode(RcodeNameError)
+
+	if soa != nil {
+		dns.AddRR(SectionAuthority, zone, soa.Minimum, soa)
+	}
+}
+
+//AddRR adds a class IN record holding rdata to a section of the packet
+//and updates the section counts
+func (dns *DNSPacket) AddRR(section Section, name string, ttl uint32, rdata PacketProcessor) *Answer {
+	answer := newRR(name, QclassIN, ttl, rdata)
+
+	var records *[]Answer
+	switch section {
+	case SectionAuthority:
+		records = &dns.Authority
+	case SectionAdditional:
+		records = &dns.Additional
+	default:
+		records = &dns.Answers
+	}
+
+	*records = append(*records, answer)
+	dns.updateCounts()
+
+	return &(*records)[len(*records)-1]
+}
+
+//returns a record holding rdata
+func newRR(name string, class int, ttl uint32, rdata PacketProcessor) Answer {
+	answer := Answer{
+		Name:  name,
+		Type:  rdata.Type(),
+		Class: class,
+		TTL:   ttl,
+		RData: rdata,
+	}
+
+	//the length uncompressed, Encode writes the length actually used
+	if data, err := rdata.Encode(make([]byte, 0), nil); err == nil {
+		answer.RdLength = len(data)
+	}
+
+	return answer
+}
+
+//sets the header counts from the sections, the OPT record included
+func (dns *DNSPacket) updateCounts() {
+	dns.Qdcount = uint16(len(dns.Questions))
+	dns.Ancount = uint16(len(dns.Answers))
+	dns.Nscount = uint16(len(dns.Authority))
+	dns.Arcount = uint16(len(dns.Additional))
+
+	if dns.EDNS != nil {
+		dns.Arcount++
+	}
+}
+
+func randomID() uint16 {
+	id := make([]byte, 2)
+	rand.Read(id)
+
+	return binary.BigEndian.Uint16(id)
+}
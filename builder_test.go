@@ -0,0 +1,106 @@
+package dnsPacket
+
+import (
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestNewQuery(t *testing.T) {
+	query := NewQuery("example.org", DNSRecordTypeAAAA)
+
+	if query.QR || !query.RD || query.Opcode != OpcodeStandardQuery {
+		t.Errorf("Fail\nGot: %s\nWant: a QUERY with rd", query.Header)
+	}
+
+	want := []Question{{Qname: "example.org", Qtype: DNSRecordTypeAAAA, Qclass: QclassIN}}
+	if !reflect.DeepEqual(query.Questions, want) || query.Qdcount != 1 {
+		t.Errorf("Fail\nGot: %v %d\nWant: %v 1", query.Questions, query.Qdcount, want)
+	}
+
+	if err := query.Validate(); err != nil {
+		t.Errorf("Fail\nGot error: %s", err)
+	}
+
+	//IDs are random, 16 queries sharing one is next to impossible
+	ids := make(map[uint16]bool)
+	for i := 0; i < 16; i++ {
+		ids[NewQuery("example.org", DNSRecordTypeA).ID] = true
+	}
+
+	if len(ids) == 1 {
+		t.Errorf("Fail\nGot: the same ID 16 times")
+	}
+}
+
+func TestReply(t *testing.T) {
+	query := NewQuery("example.org", DNSRecordTypeA)
+	query.CD = true
+	query.AddRR(SectionAdditional, "example.org", 60, &RecordTypeA{Addr: netip.MustParseAddr("192.0.2.1")})
+
+	reply := query.Reply()
+	reply.AddRR(SectionAnswer, "example.org", 300, &RecordTypeA{Addr: netip.MustParseAddr("192.0.2.2")})
+
+	if reply.ID != query.ID || !reply.QR || !reply.RD || !reply.CD || reply.Rcode != RcodeNoError {
+		t.Errorf("Fail\nGot: %s\nWant: a response to %s", reply.Header, query.Header)
+	}
+
+	if !reflect.DeepEqual(reply.Questions, query.Questions) || len(reply.Additional) != 0 {
+		t.Errorf("Fail\nGot: %v %v\nWant: %v and no additional records", reply.Questions, reply.Additional, query.Questions)
+	}
+
+	if reply.Ancount != 1 || reply.Answers[0].RdLength != 4 || reply.Answers[0].Class != QclassIN {
+		t.Errorf("Fail\nGot: %d %v\nWant: one A record", reply.Ancount, reply.Answers)
+	}
+
+	//the counts are kept up to date, so strict encoding succeeds
+	data, err := EncodeStrict(reply)
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
+	decoded, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
+	if !reflect.DeepEqual(decoded, reply) {
+		t.Errorf("Fail\nGot: %s\nWant: %s", decoded, reply)
+	}
+}
+
+func TestSetRcode(t *testing.T) {
+	reply := NewQuery("example.org", DNSRecordTypeA).Reply()
+
+	reply.SetRcode(RcodeRefused)
+	if reply.Rcode != RcodeRefused || reply.EDNS != nil || reply.Arcount != 0 {
+		t.Errorf("Fail\nGot: %s %v\nWant: REFUSED without EDNS", reply.Rcode, reply.EDNS)
+	}
+
+	reply.SetRcode(RcodeBadCookie)
+	if reply.ExtendedRcode() != RcodeBadCookie || reply.EDNS == nil || reply.Arcount != 1 {
+		t.Errorf("Fail\nGot: %s %d\nWant: BADCOOKIE with an OPT record", reply.ExtendedRcode(), reply.Arcount)
+	}
+
+	if _, err := EncodeStrict(reply); err != nil {
+		t.Errorf("Fail\nGot error: %s", err)
+	}
+}
+
+func TestSetNXDomain(t *testing.T) {
+	reply := NewQuery("www.example.org", DNSRecordTypeA).Reply()
+	reply.AA = true
+	reply.SetNXDomain("example.org", &RecordTypeSOA{Mname: "ns.example.org", Rname: "hostmaster.example.org", Serial: 1, Minimum: 300})
+
+	if !reply.IsNXDomain() || reply.Nscount != 1 {
+		t.Fatalf("Fail\nGot: %s %d\nWant: NXDOMAIN with a SOA record", reply.Rcode, reply.Nscount)
+	}
+
+	if ttl, ok := reply.NegativeTTL(); !ok || ttl != 300 {
+		t.Errorf("Fail\nGot: %d %t\nWant: 300 true", ttl, ok)
+	}
+
+	if err := reply.Validate(); err != nil {
+		t.Errorf("Fail\nGot error: %s", err)
+	}
+}
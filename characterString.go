@@ -0,0 +1,117 @@
+package dnsPacket
+
+import (
+	"strings"
+)
+
+//<character-string> (RFC 1035 3.3) is a single length octet followed
+//by up to 255 octets of data. Records made of several of them (TXT, HINFO...)
+//use these helpers
+
+const maxCharacterStringLength = 255
+
+//writes s in presentation format: in double quotes, with quotes and
+//backslashes escaped and bytes that are not printable written as \DDD
+func quoteCharacterString(s string) string {
+	return `"` + escapeText(s, `"\`) + `"`
+}
+
+//reads character-strings written in presentation format, quotes
+//already removed, undoing their escapes
+func parseCharacterStrings(fields []string) ([]string, error) {
+	if len(fields) == 0 {
+		return nil, ErrBadRdata
+	}
+
+	strs := make([]string, len(fields))
+	for i, field := range fields {
+		s, err := unescapeText(field)
+		if err != nil {
+			return nil, err
+		}
+
+		strs[i] = s
+	}
+
+	return strs, nil
+}
+
+//writes strs as space separated quoted character-strings
+func quoteCharacterStrings(strs []string) string {
+	quoted := make([]string, len(strs))
+	for i, s := range strs {
+		quoted[i] = quoteCharacterString(s)
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+//decodes the character-string at msg[off:], which must end before end,
+//and returns it with how many bytes it occupies
+func decodeCharacterString(msg []byte, off int, end int) (string, int, error) {
+	if off >= end || end > len(msg) {
+		return "", 0, ErrBadRdata
+	}
+
+	length := int(msg[off])
+	if off+1+length > end {
+		return "", 0, ErrBadRdata
+	}
+
+	return string(msg[off+1 : off+1+length]), length + 1, nil
+}
+
+//appends s to msg as a character-string
+func encodeCharacterString(msg []byte, s string) ([]byte, error) {
+	if len(s) > maxCharacterStringLength {
+		return nil, ErrCharacterStringTooLong
+	}
+
+	msg = append(msg, byte(len(s)))
+	msg = append(msg, s...)
+
+	return msg, nil
+}
+
+//decodes character-strings until length bytes at off are used up.
+//RDATA without any character-string is not valid (RFC 1035 3.3.14)
+func decodeCharacterStrings(msg []byte, off int, length int) ([]string, error) {
+	if length == 0 {
+		return nil, ErrBadRdata
+	}
+
+	strs := make([]string, 0)
+	end := off + length
+
+	for off < end {
+		s, n, err := decodeCharacterString(msg, off, end)
+		if err != nil {
+			return nil, err
+		}
+
+		strs = append(strs, s)
+		off = off + n
+	}
+
+	return strs, nil
+}
+
+//appends every string in strs to msg, splitting strings longer than
+//255 bytes into as many character-strings as needed.
+//At least one (possibly empty) character-string is always written
+func encodeCharacterStrings(msg []byte, strs []string) []byte {
+	if len(strs) == 0 {
+		return append(msg, 0)
+	}
+
+	for _, s := range strs {
+		for len(s) > maxCharacterStringLength {
+			msg, _ = encodeCharacterString(msg, s[:maxCharacterStringLength])
+			s = s[maxCharacterStringLength:]
+		}
+
+		msg, _ = encodeCharacterString(msg, s)
+	}
+
+	return msg
+}
@@ -0,0 +1,92 @@
+package dnsPacket
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTextRecordsRoundTrip(t *testing.T) {
+	long := strings.Repeat("a", 300)
+
+	tables := []struct {
+		record  PacketProcessor
+		decoded PacketProcessor
+		data    []byte
+		want    PacketProcessor
+	}{
+		{
+			&RecordTypeTXT{Text: []string{"v=1", ""}},
+			&RecordTypeTXT{},
+			[]byte{3, 118, 61, 49, 0},
+			&RecordTypeTXT{Text: []string{"v=1", ""}},
+		},
+		{
+			&RecordTypeTXT{},
+			&RecordTypeTXT{},
+			[]byte{0},
+			&RecordTypeTXT{Text: []string{""}},
+		},
+		{
+			//split into a 255 and a 45 byte character-string
+			&RecordTypeSPF{Text: []string{long}},
+			&RecordTypeSPF{},
+			append(append([]byte{255}, long[:255]...), append([]byte{45}, long[255:]...)...),
+			&RecordTypeSPF{Text: []string{long[:255], long[255:]}},
+		},
+		{
+			&RecordTypeHINFO{CPU: "ARM64", OS: "Linux"},
+			&RecordTypeHINFO{},
+			[]byte{5, 65, 82, 77, 54, 52, 5, 76, 105, 110, 117, 120},
+			&RecordTypeHINFO{CPU: "ARM64", OS: "Linux"},
+		},
+	}
+
+	for _, table := range tables {
+		data, err := table.record.Encode(nil, nil)
+		if err != nil {
+			t.Fatalf("Fail\nGot error: %s", err)
+		}
+
+		if !bytes.Equal(data, table.data) {
+			t.Errorf("Fail\nGot: %v\nWant: %v", data, table.data)
+		}
+
+		if err := table.decoded.Decode(data, 0, len(data)); err != nil {
+			t.Fatalf("Fail\nGot error: %s", err)
+		}
+
+		if !reflect.DeepEqual(table.decoded, table.want) {
+			t.Errorf("Fail\nGot: %v\nWant: %v", table.decoded, table.want)
+		}
+	}
+}
+
+func TestCharacterStringErrors(t *testing.T) {
+	//length byte promises more than the rdata holds
+	if err := (&RecordTypeTXT{}).Decode([]byte{5, 97, 98}, 0, 3); err != ErrBadRdata {
+		t.Errorf("Fail\nGot: %v\nWant: %v", err, ErrBadRdata)
+	}
+
+	//TXT and SPF hold at least one character-string
+	if err := (&RecordTypeTXT{}).Decode([]byte{}, 0, 0); err != ErrBadRdata {
+		t.Errorf("Fail\nGot: %v\nWant: %v", err, ErrBadRdata)
+	}
+
+	if err := (&RecordTypeSPF{}).Decode([]byte{}, 0, 0); err != ErrBadRdata {
+		t.Errorf("Fail\nGot: %v\nWant: %v", err, ErrBadRdata)
+	}
+
+	//HINFO without the OS string
+	if err := (&RecordTypeHINFO{}).Decode([]byte{1, 97}, 0, 2); err != ErrBadRdata {
+		t.Errorf("Fail\nGot: %v\nWant: %v", err, ErrBadRdata)
+	}
+
+	//HINFO strings are not split automatically
+	_, err := (&RecordTypeHINFO{CPU: strings.Repeat("a", 256)}).Encode(nil, nil)
+	if !errors.Is(err, ErrCharacterStringTooLong) {
+		t.Errorf("Fail\nGot: %v\nWant: %v", err, ErrCharacterStringTooLong)
+	}
+}
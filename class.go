@@ -0,0 +1,47 @@
+package dnsPacket
+
+import (
+	"strconv"
+	"strings"
+)
+
+var classNames = map[int]string{
+	QclassIN:   "IN",
+	QclassCH:   "CH",
+	QclassHS:   "HS",
+	QclassNONE: "NONE",
+	QclassANY:  "ANY",
+}
+
+//ClassToString returns the mnemonic for a class, or CLASSnnn (RFC 3597)
+//for classes without one
+func ClassToString(class int) string {
+	if name, ok := classNames[class]; ok {
+		return name
+	}
+
+	return "CLASS" + strconv.Itoa(class)
+}
+
+//StringToClass returns the class for a mnemonic, ignoring case.
+//The RFC 3597 CLASSnnn form is accepted for any class
+func StringToClass(mnemonic string) (int, bool) {
+	mnemonic = strings.ToUpper(mnemonic)
+
+	for class, name := range classNames {
+		if name == mnemonic {
+			return class, true
+		}
+	}
+
+	if !strings.HasPrefix(mnemonic, "CLASS") {
+		return 0, false
+	}
+
+	class, err := strconv.Atoi(mnemonic[5:])
+	if err != nil || class < 0 || class > 0xFFFF {
+		return 0, false
+	}
+
+	return class, true
+}
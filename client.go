@@ -0,0 +1,148 @@
+package dnsPacket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+//the defaults of a zero Client
+const (
+	defaultTimeout  = 2 * time.Second
+	defaultAttempts = 3
+)
+
+//the largest UDP payload, replies of any EDNS size fit
+const maxUDPSize = 65535
+
+//Client sends queries to a name server over UDP. The zero Client is
+//ready to use
+type Client struct {
+	//how long to wait for the first reply, doubled for every retry.
+	//2 seconds when 0
+	Timeout time.Duration
+	//how many times a query is sent before giving up, 3 when 0
+	Attempts int
+}
+
+//Exchange sends query to server, a host and port (":53" when left out),
+//and returns the reply.
+//Every attempt is sent with a new random ID from a new socket, so from a
+//random source port, and only a reply with the ID of the attempt and the
+//questions of the query is accepted; anything else is ignored as it may be
+//spoofed. query itself is not changed. Truncated replies are returned as
+//they are, check TC.
+//Once every attempt timed out the error wraps ErrNoResponse. When ctx is
+//done first its error is returned
+func (c *Client) Exchange(ctx context.Context, query *DNSPacket, server string) (*DNSPacket, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	attempts := c.Attempts
+	if attempts <= 0 {
+		attempts = defaultAttempts
+	}
+
+	for i := 0; i < attempts; i++ {
+		reply, err := c.attempt(ctx, query, server, timeout<<i)
+		if err == nil {
+			return reply, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		var netErr net.Error
+		if !errors.As(err, &netErr) || !netErr.Timeout() {
+			return nil, fmt.Errorf("dnsPacket: %s: %w", server, err)
+		}
+	}
+
+	return nil, fmt.Errorf("dnsPacket: %s: %d attempts: %w", server, attempts, ErrNoResponse)
+}
+
+//sends the query once and waits up to timeout for its reply
+func (c *Client) attempt(ctx context.Context, query *DNSPacket, server string, timeout time.Duration) (*DNSPacket, error) {
+	sent := *query
+	sent.ID = randomID()
+
+	msg, err := Encode(&sent)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.Dial("udp", server)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+
+	conn.SetDeadline(deadline)
+
+	//a cancelled context ends the read at once
+	stop := context.AfterFunc(ctx, func() {
+		conn.SetDeadline(time.Now())
+	})
+	defer stop()
+
+	if _, err := conn.Write(msg); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, maxUDPSize)
+
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return nil, err
+		}
+
+		reply, err := Decode(buf[:n])
+		if err != nil || !isReplyTo(reply, &sent) {
+			continue
+		}
+
+		return reply, nil
+	}
+}
+
+//reports whether reply answers query: a response with its ID and the
+//same questions, names compared without case
+func isReplyTo(reply *DNSPacket, query *DNSPacket) bool {
+	if !reply.QR || reply.ID != query.ID || len(reply.Questions) != len(query.Questions) {
+		return false
+	}
+
+	for i, q := range query.Questions {
+		r := reply.Questions[i]
+		if r.Qtype != q.Qtype || r.Qclass != q.Qclass {
+			return false
+		}
+
+		queryName, err := q.Name()
+		if err != nil {
+			return false
+		}
+
+		replyName, err := r.Name()
+		if err != nil || !replyName.Equal(queryName) {
+			return false
+		}
+	}
+
+	return true
+}
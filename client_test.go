@@ -0,0 +1,179 @@
+package dnsPacket
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"sync"
+	"testing"
+	"time"
+)
+
+//a name server on the loopback interface, for the length of a test
+type testServer struct {
+	addr    string
+	mu      sync.Mutex
+	queries []*DNSPacket
+}
+
+//starts a server passing every query it receives, counted from 1, to
+//handle and sending back the packets handle returns
+func startServer(t *testing.T, handle func(query *DNSPacket, count int) []*DNSPacket) *testServer {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	server := &testServer{addr: conn.LocalAddr().String()}
+
+	go func() {
+		buf := make([]byte, maxUDPSize)
+
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			query, err := Decode(buf[:n])
+			if err != nil {
+				continue
+			}
+
+			server.mu.Lock()
+			server.queries = append(server.queries, query)
+			count := len(server.queries)
+			server.mu.Unlock()
+
+			for _, reply := range handle(query, count) {
+				msg, _ := Encode(reply)
+				conn.WriteTo(msg, addr)
+			}
+		}
+	}()
+
+	return server
+}
+
+func (server *testServer) received() []*DNSPacket {
+	server.mu.Lock()
+	defer server.mu.Unlock()
+
+	return append([]*DNSPacket(nil), server.queries...)
+}
+
+//answers with 192.0.2.1
+func answer(query *DNSPacket) *DNSPacket {
+	reply := query.Reply()
+	reply.AddRR(SectionAnswer, query.Questions[0].Qname, 60, &RecordTypeA{Addr: netip.MustParseAddr("192.0.2.1")})
+
+	return reply
+}
+
+func TestClientExchange(t *testing.T) {
+	//names in the question are matched without case
+	server := startServer(t, func(query *DNSPacket, count int) []*DNSPacket {
+		reply := answer(query)
+		reply.Questions[0].Qname = "example.org"
+
+		return []*DNSPacket{reply}
+	})
+
+	query := NewQuery("Example.org", DNSRecordTypeA)
+	id := query.ID
+
+	reply, err := new(Client).Exchange(context.Background(), query, server.addr)
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
+	if len(reply.Answers) != 1 || reply.Answers[0].String() != "Example.org.\t60\tIN\tA\t192.0.2.1" {
+		t.Errorf("Fail\nGot:\n%s", reply)
+	}
+
+	if query.ID != id || reply.ID != server.received()[0].ID {
+		t.Errorf("Fail\nGot: query %d reply %d\nWant: query %d, reply with the ID sent", query.ID, reply.ID, id)
+	}
+}
+
+func TestClientIgnoresMismatch(t *testing.T) {
+	server := startServer(t, func(query *DNSPacket, count int) []*DNSPacket {
+		wrongID := answer(query)
+		wrongID.ID = query.ID + 1
+
+		wrongQuestion := answer(query)
+		wrongQuestion.Questions[0].Qtype = DNSRecordTypeAAAA
+
+		notReply := answer(query)
+		notReply.QR = false
+
+		return []*DNSPacket{wrongID, wrongQuestion, notReply, answer(query)}
+	})
+
+	reply, err := new(Client).Exchange(context.Background(), NewQuery("example.org", DNSRecordTypeA), server.addr)
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
+	if !reply.QR || reply.Questions[0].Qtype != DNSRecordTypeA || reply.ID != server.received()[0].ID {
+		t.Errorf("Fail\nGot:\n%s", reply)
+	}
+}
+
+func TestClientRetry(t *testing.T) {
+	//the first query is lost
+	server := startServer(t, func(query *DNSPacket, count int) []*DNSPacket {
+		if count == 1 {
+			return nil
+		}
+
+		return []*DNSPacket{answer(query)}
+	})
+
+	client := &Client{Timeout: 50 * time.Millisecond}
+
+	reply, err := client.Exchange(context.Background(), NewQuery("example.org", DNSRecordTypeA), server.addr)
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
+	if queries := server.received(); len(queries) != 2 || reply.ID != queries[1].ID {
+		t.Errorf("Fail\nGot: %d queries\nWant: 2, answered the second", len(queries))
+	}
+}
+
+func TestClientNoResponse(t *testing.T) {
+	server := startServer(t, func(query *DNSPacket, count int) []*DNSPacket {
+		return nil
+	})
+
+	client := &Client{Timeout: 10 * time.Millisecond, Attempts: 2}
+
+	_, err := client.Exchange(context.Background(), NewQuery("example.org", DNSRecordTypeA), server.addr)
+	if !errors.Is(err, ErrNoResponse) {
+		t.Errorf("Fail\nGot: %v\nWant: %v", err, ErrNoResponse)
+	}
+
+	if len(server.received()) != 2 {
+		t.Errorf("Fail\nGot: %d queries\nWant: 2", len(server.received()))
+	}
+}
+
+func TestClientCancel(t *testing.T) {
+	server := startServer(t, func(query *DNSPacket, count int) []*DNSPacket {
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+
+	client := &Client{Timeout: time.Minute}
+	start := time.Now()
+
+	_, err := client.Exchange(ctx, NewQuery("example.org", DNSRecordTypeA), server.addr)
+	if !errors.Is(err, context.Canceled) || time.Since(start) > 10*time.Second {
+		t.Errorf("Fail\nGot: %v after %s\nWant: %v at once", err, time.Since(start), context.Canceled)
+	}
+}
@@ -0,0 +1,208 @@
+package dnsPacket
+
+import (
+	"fmt"
+	"strings"
+)
+
+//Name compression (RFC 1035 4.1.4)
+//
+//While a message is being encoded every name suffix written to it is
+//recorded in a compression map together with the offset it starts at.
+//When a later name ends in a suffix that is already in the map, the
+//suffix is replaced by a two byte pointer to that offset.
+//
+//  www.google.com at offset 12  -> google.com = 16, com = 23
+//  mail.google.com              -> 4 mail | C0 10
+
+//appends the wire form of name to msg and returns the extended slice.
+//msg must hold the message from its first byte so offsets are correct.
+//If compress is true the longest suffix found in compression is written
+//as a pointer. Names are recorded in compression either way so they can
+//be pointed at later; a nil map disables both
+func encodeName(msg []byte, name string, compression map[string]int, compress bool) ([]byte, error) {
+	labels, err := splitLabels(name)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range labels {
+		suffix := joinLabels(labels[i:])
+
+		if offset, ok := compression[suffix]; ok && compress {
+			pointer, _ := fromIntToBytes(uint16(OffsetMarker | offset))
+			return append(msg, pointer...), nil
+		}
+
+		//pointers only have 14 bits for the offset
+		if _, ok := compression[suffix]; !ok && compression != nil && len(msg) <= CompressedAnswerMask {
+			compression[suffix] = len(msg)
+		}
+
+		msg = append(msg, byte(len(labels[i])))
+		msg = append(msg, labels[i]...)
+	}
+
+	return append(msg, 0), nil
+}
+
+//splits a name into its raw labels, undoing the \X and \DDD escapes
+//of RFC 1035 5.1, and checks the label and name length limits.
+//The root name ("" or ".") has no labels
+func splitLabels(name string) ([]string, error) {
+	labels, err := unescapeLabels(name)
+	if err != nil {
+		return nil, err
+	}
+
+	wireLength := 1 //the terminating zero length label
+
+	for _, l := range labels {
+		if len(l) > maxLabelLength {
+			return nil, ErrLabelTooLong
+		}
+
+		wireLength = wireLength + len(l) + 1
+	}
+
+	if wireLength > maxNameLength {
+		return nil, ErrNameTooLong
+	}
+
+	return labels, nil
+}
+
+//works like splitLabels but leaves the lengths unchecked, for names
+//whose labels are converted before they are written
+func unescapeLabels(name string) ([]string, error) {
+	if name == "" || name == "." {
+		return nil, nil
+	}
+
+	labels := make([]string, 0)
+	label := make([]byte, 0, maxLabelLength)
+
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+
+		switch {
+		case c == '\\':
+			b, n, err := unescape(name[i+1:])
+			if err != nil {
+				return nil, err
+			}
+
+			label = append(label, b)
+			i = i + n
+			continue
+
+		case c != '.':
+			label = append(label, c)
+			continue
+		}
+
+		//a dot ends the label, a trailing one marks the name as absolute
+		if len(label) == 0 {
+			return nil, ErrEmptyLabel
+		}
+
+		labels = append(labels, string(label))
+		label = label[:0]
+	}
+
+	//the name did not end in a dot
+	if len(label) > 0 {
+		labels = append(labels, string(label))
+	}
+
+	return labels, nil
+}
+
+//decodes the escape following a backslash and returns the byte it stands
+//for together with how many characters of s it used
+func unescape(s string) (byte, int, error) {
+	if len(s) == 0 {
+		return 0, 0, ErrBadEscape
+	}
+
+	if s[0] < '0' || s[0] > '9' {
+		return s[0], 1, nil
+	}
+
+	if len(s) < 3 {
+		return 0, 0, ErrBadEscape
+	}
+
+	value := 0
+	for _, c := range s[:3] {
+		if c < '0' || c > '9' {
+			return 0, 0, ErrBadEscape
+		}
+
+		value = value*10 + int(c-'0')
+	}
+
+	if value > 255 {
+		return 0, 0, ErrBadEscape
+	}
+
+	return byte(value), 3, nil
+}
+
+//escapes a raw label so it can be written in a name in presentation
+//format: dots, backslashes and the characters special in zone files get
+//a backslash, bytes that are not printable are written as \DDD
+func escapeLabel(label string) string {
+	return escapeText(label, ".\\\"();@$ ")
+}
+
+//escapes the bytes of s found in special with a backslash and writes
+//bytes that are not printable ASCII as \DDD
+func escapeText(s string, special string) string {
+	escaped := new(strings.Builder)
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+
+		switch {
+		case strings.IndexByte(special, c) >= 0:
+			escaped.WriteByte('\\')
+			escaped.WriteByte(c)
+		case c < 0x20 || c > 0x7E:
+			fmt.Fprintf(escaped, "\\%03d", c)
+		default:
+			escaped.WriteByte(c)
+		}
+	}
+
+	return escaped.String()
+}
+
+//returns name fully qualified, ending in a dot
+func fqdn(name string) string {
+	if name == "" || name == "." {
+		return "."
+	}
+
+	//a dot preceded by an odd number of backslashes is part of the last label
+	backslashes := 0
+	for i := len(name) - 2; i >= 0 && name[i] == '\\'; i-- {
+		backslashes++
+	}
+
+	if strings.HasSuffix(name, ".") && backslashes%2 == 0 {
+		return name
+	}
+
+	return name + "."
+}
+
+//joins raw labels back into a name
+func joinLabels(labels []string) string {
+	escaped := make([]string, len(labels))
+	for i, label := range labels {
+		escaped[i] = escapeLabel(label)
+	}
+
+	return strings.Join(escaped, ".")
+}
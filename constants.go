@@ -3,7 +3,7 @@ package dnsPacket
 /*
 0  1  2  3  4  5  6  7  8  9  A  B  C  D  E  F
 +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
-|QR|   Opcode  |AA|TC|RD|RA|   Z    |   RCODE   |
+|QR|   Opcode  |AA|TC|RD|RA| Z|AD|CD|   RCODE   |
 
 */
 
@@ -15,37 +15,93 @@ const (
 
 //Opcodes
 const (
-	OpcodeStandardQuery = 0
-	OpcodeInverseQuery  = 1
-	OpcodeServerStatus  = 2
+	OpcodeStandardQuery Opcode = 0
+	OpcodeInverseQuery  Opcode = 1
+	OpcodeServerStatus  Opcode = 2
+	OpcodeNotify        Opcode = 4
+	OpcodeUpdate        Opcode = 5
+	OpcodeDSO           Opcode = 6
 )
 
 //Rcodes
 const (
-	RcodeNoError        = 0
-	RcodeFormatError    = 1
-	RcodeServerFailure  = 2
-	RcodeNameError      = 3
-	RcodeNotImplemented = 4
-	RcodeRefused        = 5
-	RcodeMask           = 0xF
+	RcodeNoError        Rcode = 0
+	RcodeFormatError    Rcode = 1
+	RcodeServerFailure  Rcode = 2
+	RcodeNameError      Rcode = 3
+	RcodeNotImplemented Rcode = 4
+	RcodeRefused        Rcode = 5
+	RcodeYXDomain       Rcode = 6
+	RcodeYXRRSet        Rcode = 7
+	RcodeNXRRSet        Rcode = 8
+	RcodeNotAuth        Rcode = 9
+	RcodeNotZone        Rcode = 10
+	RcodeDSOTypeNI      Rcode = 11
+	RcodeMask                 = 0xF
+	maxExtendedRcode          = 0xFFF //4 header bits and 8 in the OPT record
+)
+
+//Extended Rcodes, these need EDNS (see DNSPacket.SetExtendedRcode)
+const (
+	RcodeBadVersion Rcode = 16
+	RcodeBadKey     Rcode = 17
+	RcodeBadTime    Rcode = 18
+	RcodeBadMode    Rcode = 19
+	RcodeBadName    Rcode = 20
+	RcodeBadAlg     Rcode = 21
+	RcodeBadTrunc   Rcode = 22
+	RcodeBadCookie  Rcode = 23
 )
 
 //Qclass
 const (
-	QclassIN = 1
+	QclassIN   = 1
+	QclassCH   = 3
+	QclassHS   = 4
+	QclassNONE = 254
+	QclassANY  = 255
 )
 
 //DNS Record Types
 const (
-	DNSRecordTypeA   = 1
-	DNSRecordTypeSRV = 33
+	DNSRecordTypeA     = 1
+	DNSRecordTypeNS    = 2
+	DNSRecordTypeCNAME = 5
+	DNSRecordTypeSOA   = 6
+	DNSRecordTypePTR   = 12
+	DNSRecordTypeHINFO = 13
+	DNSRecordTypeMX    = 15
+	DNSRecordTypeTXT   = 16
+	DNSRecordTypeAAAA  = 28
+	DNSRecordTypeSRV   = 33
+	DNSRecordTypeDNAME = 39
+	DNSRecordTypeOPT   = 41
+	DNSRecordTypeSPF   = 99
 )
 
 const (
 	CompressedAnswerMask = 0x3FFF
 )
 
+//size of the fixed DNS header in bytes
+const (
+	headerLength = 12
+)
+
+//name limits from RFC 1035 2.3.4. A name of at most 255 octets
+//has at most 127 labels, so no valid name needs more pointers than that
+const (
+	maxLabelLength = 63
+	maxNameLength  = 255
+	maxPointerHops = 127
+)
+
+//the header counts and RDLENGTH are 16 bit
+const (
+	maxSectionLength = 0xFFFF
+	maxRdLength      = 0xFFFF
+)
+
 //query params flags
 const (
 	FlagsOpCodeStandardQuery = 0 << 11
@@ -55,15 +111,30 @@ const (
 	FlagsTruncation          = 1 << 9
 	FlagsRecurionDesired     = 1 << 8
 	FlagsRecursionAvailable  = 1 << 7
-	FlagsMask                = 0xF00
-	ZMask                    = 0x70
-	AuthoritativeAnswerMask  = 0x400
-	TruncationMask           = 0x200
-	RecursionDesiredMask     = 0x100
-	RecursionAvailableMask   = 0x80
+	FlagsZ                   = 1 << 6
+	FlagsAuthenticData       = 1 << 5
+	FlagsCheckingDisabled    = 1 << 4
 )
 
 //1100000000000000
 const (
 	OffsetMarker = 0xC000
 )
+
+//EDNS(0)
+const (
+	DefaultUDPSize = 1232 //what most resolvers advertise since DNS flag day 2020
+	minUDPSize     = 512
+	ednsDOMask     = 0x8000
+	ednsZMask      = 0x7FFF
+)
+
+//EDNS option codes
+const (
+	EDNSOptionCodeNSID      = 3
+	EDNSOptionCodeSubnet    = 8
+	EDNSOptionCodeCookie    = 10
+	EDNSOptionCodeKeepalive = 11
+	EDNSOptionCodePadding   = 12
+	EDNSOptionCodeEDE       = 15
+)
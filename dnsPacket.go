@@ -14,9 +14,9 @@ import (
 +---------------------+
 | Answer              | Answers to the question
 +---------------------+
-| Authority           | Not used in this project
+| Authority           | Records pointing toward an authority
 +---------------------+
-| Additional          | Not used in this project
+| Additional          | Records holding additional information
 +---------------------+
 */
 
@@ -27,7 +27,7 @@ DNS HEADERS
 +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
 | ID                                            |
 +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
-|QR| Opcode     |AA|TC|RD|RA| Z | RCODE         |
+|QR| Opcode     |AA|TC|RD|RA| Z|AD|CD| RCODE     |
 +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
 | QDCOUNT                                       |
 +--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
@@ -40,23 +40,19 @@ DNS HEADERS
 */
 
 type DNSPacket struct {
-	Type       string
-	ID         uint16
-	Opcode     int
-	Z          int
-	Rcode      int
-	Flags      int
+	Header
 	Qdcount    uint16
 	Ancount    uint16
 	Nscount    uint16
 	Arcount    uint16
 	Questions  []Question
 	Answers    []Answer
-	Authority  []byte
-	Additional []byte
+	Authority  []Answer
+	Additional []Answer
+	EDNS       *RecordTypeOPT
 }
 
-//Add a Question to the DNS Packet
+//Add a Question to the DNS Packet. The header counts are updated
 func (dns *DNSPacket) AddQuestion(name string, qclass int, qtype int) *Question {
 	question := Question{
 		Qname:  name,
@@ -65,119 +61,199 @@ func (dns *DNSPacket) AddQuestion(name string, qclass int, qtype int) *Question
 	}
 
 	dns.Questions = append(dns.Questions, question)
+	dns.updateCounts()
 
 	return &question
 }
 
-//Add an Answer to the DNS Packet
+//Add an Answer to the DNS Packet. The header counts are updated
 func (dns *DNSPacket) AddAnswer(name string, aclass int, atype int, ttl uint32, dataLength int, data []byte) *Answer {
-	answer := Answer{
-		Name:     name,
-		Class:    aclass,
-		Type:     atype,
-		TTL:      ttl,
-		RdLength: dataLength,
-	}
-	answer.Data = make([]byte, len(data))
-	for i := range data {
-		answer.Data[i] = data[i]
-	}
+	answer := newAnswer(name, aclass, atype, ttl, dataLength, data)
 
 	dns.Answers = append(dns.Answers, answer)
+	dns.updateCounts()
+
+	return &answer
+}
+
+//Add a record to the Authority section of the DNS Packet. The header
+//counts are updated
+func (dns *DNSPacket) AddAuthority(name string, aclass int, atype int, ttl uint32, dataLength int, data []byte) *Answer {
+	answer := newAnswer(name, aclass, atype, ttl, dataLength, data)
+
+	dns.Authority = append(dns.Authority, answer)
+	dns.updateCounts()
+
+	return &answer
+}
+
+//Add a record to the Additional section of the DNS Packet. The header
+//counts are updated
+func (dns *DNSPacket) AddAdditional(name string, aclass int, atype int, ttl uint32, dataLength int, data []byte) *Answer {
+	answer := newAnswer(name, aclass, atype, ttl, dataLength, data)
+
+	dns.Additional = append(dns.Additional, answer)
+	dns.updateCounts()
 
 	return &answer
 }
 
 //Check the AA flag of the DNS Packet
 func (dns *DNSPacket) IsAuthoritativeAnswer() bool {
-	if (dns.Flags & AuthoritativeAnswerMask) > 0 {
-		return true
-	}
-
-	return false
+	return dns.AA
 }
 
 //Check the TC flag of the DNS Packet
 func (dns *DNSPacket) IsTruncated() bool {
-	if (dns.Flags & TruncationMask) > 0 {
-		return true
-	}
-
-	return false
+	return dns.TC
 }
 
 //Check the RD flag of the DNS Packet
 func (dns *DNSPacket) IsRecursionDesired() bool {
-	if (dns.Flags & RecursionDesiredMask) > 0 {
-		return true
-	}
-
-	return false
+	return dns.RD
 }
 
 //Check the RA flag of the DNS Packet
 func (dns *DNSPacket) IsRecursionAvailable() bool {
-	if (dns.Flags & RecursionAvailableMask) > 0 {
-		return true
-	}
-
-	return false
+	return dns.RA
 }
 
+//String writes the packet the way dig does: the header, the OPT
+//pseudosection and every section that is not empty, records in
+//presentation format. The counts are those Encode would write
 func (dns DNSPacket) String() string {
+	return dns.format(false)
+}
+
+//UnicodeString works like String but writes names holding A-labels in
+//Unicode, see ToUnicode
+func (dns DNSPacket) UnicodeString() string {
+	return dns.format(true)
+}
+
+func (dns DNSPacket) format(unicode bool) string {
 	buf := new(bytes.Buffer)
 
-	buf.WriteString(fmt.Sprintf("Type: %s\n", dns.Type))
-	buf.WriteString(fmt.Sprintf("ID: %d\n", dns.ID))
-	buf.WriteString(fmt.Sprintf("Opcode: %d\n", dns.Opcode))
-	buf.WriteString(fmt.Sprintf("Z: %d\n", dns.Z))
-	buf.WriteString(fmt.Sprintf("Rcode: %d\n", dns.Rcode))
-	buf.WriteString(fmt.Sprintf("Flags:\n"))
-	buf.WriteString(fmt.Sprintf(" --AA: %t\n", dns.IsAuthoritativeAnswer()))
-	buf.WriteString(fmt.Sprintf(" --TC: %t\n", dns.IsTruncated()))
-	buf.WriteString(fmt.Sprintf(" --RD: %t\n", dns.IsRecursionDesired()))
-	buf.WriteString(fmt.Sprintf(" --RA: %t\n", dns.IsRecursionAvailable()))
-	buf.WriteString(fmt.Sprintf("Question Count: %d\n", dns.Qdcount))
-	buf.WriteString(fmt.Sprintf("Answer Count: %d\n", dns.Ancount))
-	buf.WriteString(fmt.Sprintf("NS Count: %d\n", dns.Nscount))
-	buf.WriteString(fmt.Sprintf("AR Count: %d\n", dns.Arcount))
-	buf.WriteString(fmt.Sprintf("Questions:\n"))
-
-	for i, q := range dns.Questions {
-		buf.WriteString(fmt.Sprintf("%d - %s\n", i, q))
+	additional := len(dns.Additional)
+	if dns.EDNS != nil {
+		additional++
 	}
 
-	buf.WriteString(fmt.Sprintf("Answers:\n"))
+	buf.WriteString(fmt.Sprintf(";; ->>HEADER<<- opcode: %s, status: %s, id: %d\n", dns.Opcode, dns.ExtendedRcode(), dns.ID))
+	buf.WriteString(fmt.Sprintf(";; flags:%s; QUERY: %d, ANSWER: %d, AUTHORITY: %d, ADDITIONAL: %d\n",
+		dns.flagNames(), len(dns.Questions), len(dns.Answers), len(dns.Authority), additional))
+
+	if dns.EDNS != nil {
+		buf.WriteString(fmt.Sprintf("\n;; OPT PSEUDOSECTION:\n%s\n", dns.EDNS))
+	}
 
-	for i, a := range dns.Answers {
-		buf.WriteString(fmt.Sprintf("%d - %s\n", i, a))
+	if len(dns.Questions) > 0 {
+		buf.WriteString("\n;; QUESTION SECTION:\n")
 	}
 
-	buf.WriteString("\n")
+	for _, q := range dns.Questions {
+		if unicode {
+			buf.WriteString(fmt.Sprintf("%s\n", q.UnicodeString()))
+		} else {
+			buf.WriteString(fmt.Sprintf("%s\n", q))
+		}
+	}
+
+	sections := []struct {
+		name    string
+		records []Answer
+	}{
+		{"ANSWER", dns.Answers},
+		{"AUTHORITY", dns.Authority},
+		{"ADDITIONAL", dns.Additional},
+	}
+
+	for _, section := range sections {
+		if len(section.records) == 0 {
+			continue
+		}
+
+		buf.WriteString(fmt.Sprintf("\n;; %s SECTION:\n", section.name))
+
+		for _, a := range section.records {
+			if unicode {
+				buf.WriteString(fmt.Sprintf("%s\n", a.UnicodeString()))
+			} else {
+				buf.WriteString(fmt.Sprintf("%s\n", a))
+			}
+		}
+	}
 
 	return buf.String()
 }
 
-//Encode a DNS packet and get the resulting bytes back
-func Encode(dnsPacket *DNSPacket) []byte {
+//Encode a DNS packet and get the resulting bytes back.
+//Packets that cannot be written faithfully, for example with a label
+//longer than 63 octets or an rcode that needs EDNS, return an error
+//wrapping one of the ErrXXX values instead.
+//The section counts written to the header are taken from the length of
+//Questions, Answers, Authority and Additional. Qdcount, Ancount, Nscount
+//and Arcount are ignored, use EncodeStrict to have them checked instead
+func Encode(dnsPacket *DNSPacket) ([]byte, error) {
+	return encode(dnsPacket, false)
+}
+
+//EncodeStrict works like Encode but returns an error wrapping
+//ErrCountMismatch if a count field does not match its section
+func EncodeStrict(dnsPacket *DNSPacket) ([]byte, error) {
+	return encode(dnsPacket, true)
+}
+
+func encode(dnsPacket *DNSPacket, strict bool) ([]byte, error) {
 	packet := make([]byte, 0)
-	isQuery := dnsPacket.Type == "query"
-	var packetType = 0
-	if isQuery {
-		packetType = DNSQuery
-	} else {
-		packetType = DNSResponse
+
+	//the header only has room for 4 bits of each
+	if dnsPacket.Opcode > 0xF {
+		return nil, fmt.Errorf("dnsPacket: opcode %d: %w", dnsPacket.Opcode, ErrBadOpcode)
+	}
+
+	if dnsPacket.Rcode > maxExtendedRcode {
+		return nil, fmt.Errorf("dnsPacket: rcode %s is longer than 12 bits: %w", dnsPacket.Rcode, ErrBadRcode)
+	}
+
+	if dnsPacket.Rcode > RcodeMask {
+		return nil, fmt.Errorf("dnsPacket: rcode %s: %w", dnsPacket.Rcode, ErrBadRcode)
+	}
+
+	//the OPT record is written to the additional section
+	additional, err := dnsPacket.additionalWithOPT()
+	if err != nil {
+		return nil, err
+	}
+
+	counts := []struct {
+		section string
+		count   uint16
+		length  int
+	}{
+		{"questions", dnsPacket.Qdcount, len(dnsPacket.Questions)},
+		{"answers", dnsPacket.Ancount, len(dnsPacket.Answers)},
+		{"authority records", dnsPacket.Nscount, len(dnsPacket.Authority)},
+		{"additional records", dnsPacket.Arcount, len(additional)},
+	}
+
+	for _, c := range counts {
+		if c.length > maxSectionLength {
+			return nil, fmt.Errorf("dnsPacket: %d %s: %w", c.length, c.section, ErrSectionTooLong)
+		}
+
+		if strict && int(c.count) != c.length {
+			return nil, fmt.Errorf("dnsPacket: %d %s but count is %d: %w", c.length, c.section, c.count, ErrCountMismatch)
+		}
 	}
 
 	//header
 	packetID, _ := fromIntToBytes(uint16(dnsPacket.ID))
-	params := packetType | dnsPacket.Opcode | dnsPacket.Flags | dnsPacket.Z | dnsPacket.Rcode
-
-	queryParms, _ := fromIntToBytes(uint16(params))
-	qcount, _ := fromIntToBytes(uint16(dnsPacket.Qdcount))
-	ancount, _ := fromIntToBytes(uint16(dnsPacket.Ancount))
-	nscount, _ := fromIntToBytes(uint16(dnsPacket.Nscount))
-	arcount, _ := fromIntToBytes(uint16(dnsPacket.Arcount))
+	queryParms, _ := fromIntToBytes(dnsPacket.Header.flags())
+	qcount, _ := fromIntToBytes(uint16(len(dnsPacket.Questions)))
+	ancount, _ := fromIntToBytes(uint16(len(dnsPacket.Answers)))
+	nscount, _ := fromIntToBytes(uint16(len(dnsPacket.Authority)))
+	arcount, _ := fromIntToBytes(uint16(len(additional)))
 
 	packet = append(packet, packetID...)   //2 bytes
 	packet = append(packet, queryParms...) //2 bytes
@@ -186,28 +262,36 @@ func Encode(dnsPacket *DNSPacket) []byte {
 	packet = append(packet, nscount...)    //2 bytes
 	packet = append(packet, arcount...)    //2 bytes
 
-	//todo: make encodeQuestion a method of the Question struct...
-	var startOfQuestions = 12
+	//names are compressed against every name written before them
+	compression := make(map[string]int)
 	for _, q := range dnsPacket.Questions {
-		packet = append(packet, encodeQuestion(q)...)
+		packet, err = appendQuestion(packet, q, compression)
+		if err != nil {
+			return nil, fmt.Errorf("dnsPacket: question %q: %w", q.Qname, err)
+		}
 	}
 
-	for _, a := range dnsPacket.Answers {
-
-		if dnsPacket.Qdcount > 0 { //compress the answer name
-			packet = append(packet, a.Encode(startOfQuestions)...)
-		} else {
-			packet = append(packet, a.Encode(0)...)
+	sections := [][]Answer{dnsPacket.Answers, dnsPacket.Authority, additional}
+	for _, section := range sections {
+		for _, a := range section {
+			packet, err = a.Encode(packet, compression)
+			if err != nil {
+				return nil, err
+			}
 		}
-
 	}
 
-	return packet
+	return packet, nil
 
 }
 
-//Decode a packet and get an instance of DNS packet back
-func Decode(packet []byte) *DNSPacket {
+//Decode a packet and get an instance of DNS packet back.
+//Decode never panics. If the packet is malformed a *DecodeError
+//describing the first problem is returned instead
+func Decode(packet []byte) (*DNSPacket, error) {
+	if len(packet) < headerLength {
+		return nil, &DecodeError{Offset: 0, Err: ErrShortHeader}
+	}
 
 	//header values
 	id := decodePart(packet, 0, 2)
@@ -216,91 +300,88 @@ func Decode(packet []byte) *DNSPacket {
 	anCount := decodePart(packet, 6, 8)
 	nsCount := decodePart(packet, 8, 10)
 	arCount := decodePart(packet, 10, 12)
-	isQuery := queryParams & DNSResponse
-	opcode := (queryParams << 1) >> 12
-	flags := queryParams & FlagsMask
-	z := (queryParams & ZMask) >> 4
-	rcode := (queryParams & RcodeMask)
-
-	var queryType string
-	if isQuery > 0 {
-		queryType = "response"
-	} else {
-		queryType = "query"
-	}
 
 	dnsPacket := DNSPacket{
-		Type:    queryType,
-		ID:      id,
-		Opcode:  decodeOpcode(int(opcode)),
-		Flags:   int(flags),
+		Header:  decodeHeader(id, queryParams),
 		Qdcount: qdCount,
 		Ancount: anCount,
 		Nscount: nsCount,
 		Arcount: arCount,
-		Rcode:   int(rcode),
-		Z:       int(z),
 	}
-	//process questions
-	startOfQuestions := 12
+
+	//a single cursor walks the packet, advancing by the bytes each
+	//question and record occupies
+	cursor := headerLength
+
 	for i := 0; i < int(qdCount); i++ {
-		qname, qtype, qclass, n := decodeQuestion(packet[startOfQuestions:])
+		if cursor == len(packet) {
+			return nil, &DecodeError{Offset: cursor, Err: ErrCountMismatch}
+		}
+
+		qname, qtype, qclass, n, err := decodeQuestion(packet, cursor)
+		if err != nil {
+			return nil, &DecodeError{Offset: cursor, Err: err}
+		}
 
-		startOfQuestions = startOfQuestions + n + 4
+		cursor = cursor + n
 
-		dnsPacket.AddQuestion(qname, int(qclass), int(qtype))
+		//appended directly, the counts are kept as the header has them
+		dnsPacket.Questions = append(dnsPacket.Questions, Question{Qname: qname, Qtype: int(qtype), Qclass: int(qclass)})
 
 	}
 
-	//process answers
-	startOfAnswers := startOfQuestions
+	//answer, authority and additional records all share the same format
+	var err error
 
-	for i := 0; i < int(anCount); i++ {
+	dnsPacket.Answers, cursor, err = decodeSection(packet, cursor, int(anCount))
+	if err != nil {
+		return nil, err
+	}
 
-		var offset uint16
-		var startOfAnswerType int
-		var answerName string
+	dnsPacket.Authority, cursor, err = decodeSection(packet, cursor, int(nsCount))
+	if err != nil {
+		return nil, err
+	}
 
-		//a dns packet could have 0 questions...
-		//in that case the answer name will not be in a compressed format
-		if dnsPacket.Qdcount <= 0 {
-			offset = uint16(startOfAnswers)
-			answer, n := decodeQname(packet[offset:])
-			startOfAnswerType = startOfAnswers + n
-			answerName = answer
+	startOfAdditional := cursor
+	dnsPacket.Additional, cursor, err = decodeSection(packet, cursor, int(arCount))
+	if err != nil {
+		return nil, err
+	}
 
-		} else {
-			compressedAnswerName := decodePart(packet, startOfAnswers, startOfAnswers+2)
-			offset = compressedAnswerName & CompressedAnswerMask
-			answer, _ := decodeQname(packet[offset:])
-			startOfAnswerType = startOfAnswers + 2
-			answerName = answer
-		}
+	if err := dnsPacket.extractOPT(); err != nil {
+		return nil, &DecodeError{Offset: startOfAdditional, Err: err}
+	}
 
-		//calculate bounds for answer parts (answerType,answerClass, TTL, dataLength and Data)
-		endOfAnswerType := startOfAnswerType + 2 //2 bytes for answerType
-		startOfAnswerClass := endOfAnswerType
-		endOfAnswerClass := startOfAnswerClass + 2 //2 bytes for answerClass
-		startOfTTL := endOfAnswerClass
-		endOfTTL := startOfTTL + 4 //4 bytes for TTL
-		startOfDataLength := endOfTTL
-		endOfDataLength := startOfDataLength + 2 //2bytes for dataLength
-		startOfData := endOfDataLength
+	if cursor != len(packet) {
+		return nil, &DecodeError{Offset: cursor, Err: ErrTrailingData}
+	}
 
-		anType := decodePart(packet, startOfAnswerType, endOfAnswerType)
-		anClass := decodePart(packet, startOfAnswerClass, endOfAnswerClass)
-		ttl := binary.BigEndian.Uint32(packet[startOfTTL:endOfTTL])
-		dataLength := decodePart(packet, startOfDataLength, endOfDataLength)
-		endOfData := startOfData + int(dataLength)
+	return &dnsPacket, nil
 
-		startOfAnswers = startOfAnswers + endOfData
+}
 
-		dnsPacket.AddAnswer(answerName, int(anClass), int(anType), ttl, int(dataLength), packet[startOfData:endOfData])
+//decodes count records starting at msg[off:] and returns them
+//with the offset just past the last one
+func decodeSection(msg []byte, off int, count int) ([]Answer, int, error) {
+	var records []Answer
 
-	}
+	for i := 0; i < count; i++ {
+		//the packet ended cleanly, the header promised more records than it holds
+		if off == len(msg) {
+			return nil, off, &DecodeError{Offset: off, Err: ErrCountMismatch}
+		}
 
-	return &dnsPacket
+		answer, n, err := decodeAnswer(msg, off)
+		if err != nil {
+			return nil, off, err
+		}
 
+		records = append(records, answer)
+		off = off + n
+	}
+
+	return records, off, nil
 }
 
 func fromIntToBytes(num uint16) ([]byte, error) {
@@ -327,54 +408,10 @@ func fromUint32ToBytes(num uint32) ([]byte, error) {
 	return buffer.Bytes(), nil
 }
 
-func decodeOpcode(opcode int) int {
-	var oc int
-
-	if code := opcode & OpcodeStandardQuery; code > 0 {
-		oc = code
-	}
-
-	if code := opcode & OpcodeInverseQuery; code > 0 {
-		oc = code
-	}
-
-	if code := opcode & OpcodeServerStatus; code > 0 {
-		oc = code
-	}
-
-	return oc
-}
-
-func decodeRcode(rcode int) int {
-	var rc int
-
-	if code := rcode & RcodeNoError; code > 0 {
-		rc = code
-	}
-
-	if code := rcode & RcodeFormatError; code > 0 {
-		rc = code
-	}
-
-	if code := rcode & RcodeServerFailure; code > 0 {
-		rc = code
-	}
-
-	if code := rcode & RcodeNameError; code > 0 {
-		rc = code
-	}
-
-	if code := rcode & RcodeNotImplemented; code > 0 {
-		rc = code
-	}
-
-	if code := rcode & RcodeRefused; code > 0 {
-		rc = code
-	}
-
-	return rc
-}
-
 func decodePart(packet []byte, start int, end int) uint16 {
 	return binary.BigEndian.Uint16(packet[start:end])
 }
+
+func decodeUint32(packet []byte, start int) uint32 {
+	return binary.BigEndian.Uint32(packet[start : start+4])
+}
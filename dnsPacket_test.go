@@ -1,6 +1,10 @@
 package dnsPacket
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/netip"
 	"reflect"
 	"testing"
 )
@@ -8,12 +12,7 @@ import (
 //Test Encoding a query with a single question
 func TestEncodeSingleQuestion(t *testing.T) {
 	packet := DNSPacket{
-		Type:    "query",
-		ID:      1,
-		Opcode:  0,
-		Z:       0,
-		Rcode:   0,
-		Flags:   FlagsRecurionDesired,
+		Header:  Header{ID: 1, RD: true},
 		Qdcount: 1,
 		Ancount: 0,
 		Nscount: 0,
@@ -24,7 +23,10 @@ func TestEncodeSingleQuestion(t *testing.T) {
 
 	encoded := []byte{0, 1, 1, 0, 0, 1, 0, 0, 0, 0, 0, 0, 6, 103, 111, 111, 103, 108, 101, 3, 99, 111, 109, 0, 0, 1, 0, 1}
 
-	data := Encode(&packet)
+	data, err := Encode(&packet)
+	if err != nil {
+		t.Fatalf("Failed.\nGot error: %s", err)
+	}
 
 	for i := range data {
 		if data[i] != encoded[i] {
@@ -35,12 +37,7 @@ func TestEncodeSingleQuestion(t *testing.T) {
 
 func TestEncodeMultipleQuestions(t *testing.T) {
 	packet := DNSPacket{
-		Type:    "query",
-		ID:      1,
-		Opcode:  0,
-		Z:       0,
-		Rcode:   0,
-		Flags:   FlagsRecurionDesired,
+		Header:  Header{ID: 1, RD: true},
 		Qdcount: 2,
 		Ancount: 0,
 		Nscount: 0,
@@ -52,7 +49,10 @@ func TestEncodeMultipleQuestions(t *testing.T) {
 
 	encoded := []byte{0, 1, 1, 0, 0, 2, 0, 0, 0, 0, 0, 0, 6, 103, 111, 111, 103, 108, 101, 3, 99, 111, 109, 0, 0, 1, 0, 1, 6, 103, 111, 111, 103, 108, 101, 3, 99, 111, 109, 0, 0, 1, 0, 1}
 
-	data := Encode(&packet)
+	data, err := Encode(&packet)
+	if err != nil {
+		t.Fatalf("Failed.\nGot error: %s", err)
+	}
 
 	for i := range data {
 		if data[i] != encoded[i] {
@@ -65,15 +65,14 @@ func TestEncodeMultipleQuestions(t *testing.T) {
 func TestDecodeQueryWithSingleQuestion(t *testing.T) {
 	data := []byte{0, 1, 1, 0, 0, 1, 0, 0, 0, 0, 0, 0, 6, 103, 111, 111, 103, 108, 101, 3, 99, 111, 109, 0, 0, 1, 0, 1}
 
-	packet := Decode(data)
+	packet, err := Decode(data)
+
+	if err != nil {
+		t.Fatalf("Fail.\nGot error: %s", err)
+	}
 
 	compare := DNSPacket{
-		Type:    "query",
-		ID:      1,
-		Opcode:  0,
-		Z:       0,
-		Rcode:   0,
-		Flags:   FlagsRecurionDesired,
+		Header:  Header{ID: 1, RD: true},
 		Qdcount: 1,
 		Ancount: 0,
 		Nscount: 0,
@@ -90,15 +89,14 @@ func TestDecodeQueryWithSingleQuestion(t *testing.T) {
 func TestDecodeQueryWithMultipleQuestions(t *testing.T) {
 	data := []byte{0, 1, 1, 0, 0, 2, 0, 0, 0, 0, 0, 0, 6, 103, 111, 111, 103, 108, 101, 3, 99, 111, 109, 0, 0, 1, 0, 1, 6, 103, 111, 111, 103, 108, 101, 3, 99, 111, 109, 0, 0, 1, 0, 1}
 
-	packet := Decode(data)
+	packet, err := Decode(data)
+
+	if err != nil {
+		t.Fatalf("Fail.\nGot error: %s", err)
+	}
 
 	compare := DNSPacket{
-		Type:    "query",
-		ID:      1,
-		Opcode:  0,
-		Z:       0,
-		Rcode:   0,
-		Flags:   FlagsRecurionDesired,
+		Header:  Header{ID: 1, RD: true},
 		Qdcount: 2,
 		Ancount: 0,
 		Nscount: 0,
@@ -115,12 +113,7 @@ func TestDecodeQueryWithMultipleQuestions(t *testing.T) {
 
 func TestPacketFlags(t *testing.T) {
 	packet := DNSPacket{
-		Type:    "query",
-		ID:      1,
-		Opcode:  0,
-		Z:       0,
-		Rcode:   0,
-		Flags:   FlagsRecurionDesired | FlagsAuthoritativeAnswer | FlagsTruncation | FlagsRecursionAvailable,
+		Header:  Header{ID: 1, AA: true, TC: true, RD: true, RA: true},
 		Qdcount: 1,
 		Ancount: 0,
 		Nscount: 0,
@@ -138,3 +131,472 @@ func TestPacketFlags(t *testing.T) {
 		t.Errorf("Failed.\nGot: %t %t %t %t \nWant: %t %t %t %t", isRd, isAA, isTC, isRA, true, true, true, true)
 	}
 }
+
+func TestHeaderRoundTrip(t *testing.T) {
+	tables := []struct {
+		header Header
+		flags  []byte
+	}{
+		{Header{ID: 1, RD: true}, []byte{1, 0}},
+		{Header{ID: 2, QR: true, AA: true, RD: true, RA: true, Rcode: RcodeNameError}, []byte{133, 131}},
+		{Header{ID: 3, QR: true, RD: true, RA: true, AD: true}, []byte{129, 160}},
+		{Header{ID: 4, RD: true, CD: true}, []byte{1, 16}},
+		{Header{ID: 5, Opcode: OpcodeNotify, AA: true}, []byte{36, 0}},
+		{Header{ID: 6, Opcode: OpcodeUpdate}, []byte{40, 0}},
+		{Header{ID: 7, Opcode: OpcodeDSO}, []byte{48, 0}},
+		{Header{ID: 8, QR: true, Opcode: 15, Z: true, Rcode: 15}, []byte{248, 79}},
+	}
+
+	for _, table := range tables {
+		encoded, err := Encode(&DNSPacket{Header: table.header})
+		if err != nil {
+			t.Fatalf("Fail\nGot error: %s", err)
+		}
+
+		if !reflect.DeepEqual(encoded[2:4], table.flags) {
+			t.Errorf("Fail\nGot: %v\nWant: %v", encoded[2:4], table.flags)
+		}
+
+		decoded, err := Decode(encoded)
+		if err != nil {
+			t.Fatalf("Fail\nGot error: %s", err)
+		}
+
+		if decoded.Header != table.header {
+			t.Errorf("Fail\nGot: %s\nWant: %s", decoded.Header, table.header)
+		}
+	}
+}
+
+func TestHeaderString(t *testing.T) {
+	header := Header{ID: 9, QR: true, Opcode: OpcodeUpdate, RD: true, AD: true, Rcode: RcodeNotAuth}
+	want := ";; ->>HEADER<<- opcode: UPDATE, status: NOTAUTH, id: 9\n;; flags: qr rd ad;"
+
+	if header.String() != want {
+		t.Errorf("Fail\nGot: %s\nWant: %s", header, want)
+	}
+
+	if s := Opcode(3).String(); s != "OPCODE3" {
+		t.Errorf("Fail\nGot: %s\nWant: %s", s, "OPCODE3")
+	}
+
+	if s := RcodeBadCookie.String(); s != "BADCOOKIE" {
+		t.Errorf("Fail\nGot: %s\nWant: %s", s, "BADCOOKIE")
+	}
+
+	if s := Rcode(4000).String(); s != "RCODE4000" {
+		t.Errorf("Fail\nGot: %s\nWant: %s", s, "RCODE4000")
+	}
+}
+
+func TestAnswerString(t *testing.T) {
+	soa := &RecordTypeSOA{Mname: "ns.example.org", Rname: "hostmaster.example.org", Serial: 2024010101, Refresh: 7200, Retry: 3600, Expire: 1209600, Minimum: 300}
+
+	tables := []struct {
+		answer Answer
+		out    string
+	}{
+		{Answer{Name: "example.com", Type: DNSRecordTypeA, Class: QclassIN, TTL: 300, RData: &RecordTypeA{Addr: netip.MustParseAddr("10.0.0.1")}}, "example.com.\t300\tIN\tA\t10.0.0.1"},
+		{Answer{Name: "example.com.", Type: DNSRecordTypeAAAA, Class: QclassIN, TTL: 60, RData: &RecordTypeAAAA{Addr: netip.MustParseAddr("2001:db8::1")}}, "example.com.\t60\tIN\tAAAA\t2001:db8::1"},
+		{Answer{Name: "www.example.com", Type: DNSRecordTypeCNAME, Class: QclassIN, TTL: 60, RData: &RecordTypeCNAME{Target: "example.com"}}, "www.example.com.\t60\tIN\tCNAME\texample.com."},
+		{Answer{Name: "example.com", Type: DNSRecordTypeMX, Class: QclassIN, TTL: 60, RData: &RecordTypeMX{Preference: 10, Exchange: "mail.example.com"}}, "example.com.\t60\tIN\tMX\t10 mail.example.com."},
+		{Answer{Name: "_sip._tcp.example.com", Type: DNSRecordTypeSRV, Class: QclassIN, TTL: 60, RData: &RecordTypeSRV{Priority: 1, Weight: 5, Port: 5060, Target: "sip.example.com"}}, "_sip._tcp.example.com.\t60\tIN\tSRV\t1 5 5060 sip.example.com."},
+		{Answer{Name: "example.com", Type: DNSRecordTypeTXT, Class: QclassIN, TTL: 60, RData: &RecordTypeTXT{Text: []string{"v=spf1 -all", `say "hi"`, "tab\there"}}}, "example.com.\t60\tIN\tTXT\t\"v=spf1 -all\" \"say \\\"hi\\\"\" \"tab\\009here\""},
+		{Answer{Name: "example.com", Type: DNSRecordTypeHINFO, Class: QclassIN, TTL: 60, RData: &RecordTypeHINFO{CPU: "x86_64", OS: "Linux"}}, "example.com.\t60\tIN\tHINFO\t\"x86_64\" \"Linux\""},
+		{Answer{Name: "example.org", Type: DNSRecordTypeSOA, Class: QclassIN, TTL: 3600, RData: soa}, "example.org.\t3600\tIN\tSOA\tns.example.org. hostmaster.example.org. 2024010101 7200 3600 1209600 300"},
+		{Answer{Name: "version.bind", Type: DNSRecordTypeTXT, Class: QclassCH, TTL: 0, RData: &RecordTypeTXT{Text: []string{"9.18"}}}, "version.bind.\t0\tCH\tTXT\t\"9.18\""},
+		{Answer{Name: "example.com", Type: 65281, Class: 65280, TTL: 60, RData: &RecordTypeDefault{Code: 65281, Data: []byte{10, 0, 0, 1}}}, "example.com.\t60\tCLASS65280\tTYPE65281\t\\# 4 0a000001"},
+		{Answer{Name: "example.com", Type: testPrivateType, Class: QclassIN, TTL: 60, RData: &recordTypePrivate{Value: 258}}, "example.com.\t60\tIN\tPRIVATE\t\\# 2 0102"},
+		{Answer{Name: "example.com", Type: DNSRecordTypeA, Class: QclassIN, TTL: 60, Data: []byte{10, 0, 0, 2}}, "example.com.\t60\tIN\tA\t\\# 4 0a000002"},
+		{Answer{Name: "", Type: DNSRecordTypeNS, Class: QclassIN, TTL: 60, RData: &RecordTypeNS{Host: "a.root-servers.net"}}, ".\t60\tIN\tNS\ta.root-servers.net."},
+	}
+
+	for _, table := range tables {
+		if out := table.answer.String(); out != table.out {
+			t.Errorf("Fail\nGot:  %s\nWant: %s", out, table.out)
+		}
+	}
+}
+
+func TestPacketString(t *testing.T) {
+	packet := DNSPacket{Header: Header{ID: 1, QR: true, RD: true, RA: true}}
+	packet.AddQuestion("example.com", QclassIN, DNSRecordTypeA)
+	packet.AddRR(SectionAnswer, "example.com", 300, &RecordTypeA{Addr: netip.MustParseAddr("10.0.0.1")})
+	packet.EDNS = NewOPT(DefaultUDPSize)
+	packet.EDNS.Options = []EDNSOption{&EDNSOptionNSID{ID: []byte("ns1")}}
+	packet.SetExtendedRcode(RcodeBadCookie)
+
+	want := `;; ->>HEADER<<- opcode: QUERY, status: BADCOOKIE, id: 1
+;; flags: qr rd ra; QUERY: 1, ANSWER: 1, AUTHORITY: 0, ADDITIONAL: 1
+
+;; OPT PSEUDOSECTION:
+; EDNS: version: 0, flags:; udp: 1232
+; NSID: 6e7331 ("ns1")
+
+;; QUESTION SECTION:
+;example.com.	IN	A
+
+;; ANSWER SECTION:
+example.com.	300	IN	A	10.0.0.1
+`
+
+	if out := packet.String(); out != want {
+		t.Errorf("Fail\nGot:\n%s\nWant:\n%s", out, want)
+	}
+}
+
+func TestDecodeMalformed(t *testing.T) {
+	tables := []struct {
+		name string
+		in   []byte
+		err  error
+	}{
+		{
+			"short header",
+			[]byte{0, 1, 1, 0, 0, 1},
+			ErrShortHeader,
+		},
+		{
+			"label past end",
+			[]byte{0, 1, 1, 0, 0, 1, 0, 0, 0, 0, 0, 0, 6, 103, 111, 111},
+			ErrLabelOverflow,
+		},
+		{
+			"question without type and class",
+			[]byte{0, 1, 1, 0, 0, 1, 0, 0, 0, 0, 0, 0, 3, 99, 111, 109, 0, 0, 1},
+			ErrShortBuffer,
+		},
+		{
+			"rdlength past end",
+			[]byte{0, 1, 129, 0, 0, 0, 0, 1, 0, 0, 0, 0, 3, 99, 111, 109, 0, 0, 1, 0, 1, 0, 0, 0, 60, 0, 200, 10, 0, 0, 1},
+			ErrBadRdLength,
+		},
+		{
+			"trailing garbage",
+			[]byte{0, 1, 1, 0, 0, 1, 0, 0, 0, 0, 0, 0, 3, 99, 111, 109, 0, 0, 1, 0, 1, 255, 255},
+			ErrTrailingData,
+		},
+	}
+
+	for _, table := range tables {
+		packet, err := Decode(table.in)
+
+		if packet != nil {
+			t.Errorf("%s: Fail.\nGot packet: %s\nWant: nil", table.name, packet)
+		}
+
+		var decodeErr *DecodeError
+		if !errors.As(err, &decodeErr) || !errors.Is(err, table.err) {
+			t.Errorf("%s: Fail.\nGot error: %v\nWant: %v", table.name, err, table.err)
+		}
+	}
+}
+
+func FuzzDecode(f *testing.F) {
+	f.Add([]byte{0, 1, 1, 0, 0, 1, 0, 0, 0, 0, 0, 0, 6, 103, 111, 111, 103, 108, 101, 3, 99, 111, 109, 0, 0, 1, 0, 1})
+	f.Add([]byte{0, 1, 129, 0, 0, 1, 0, 1, 0, 0, 0, 0, 3, 99, 111, 109, 0, 0, 1, 0, 1, 192, 12, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4, 10, 0, 0, 1})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		packet, err := Decode(data)
+		if err != nil {
+			if packet != nil {
+				t.Fatalf("Decode returned both a packet and an error: %s", err)
+			}
+			return
+		}
+
+		//anything Decode accepts must encode again
+		if _, err := Encode(packet); err != nil {
+			t.Fatalf("Encode failed on a decoded packet: %s", err)
+		}
+	})
+}
+
+func TestEncodeCompressesAgainstEarlierNames(t *testing.T) {
+	packet := DNSPacket{
+		Header:  Header{ID: 1, QR: true},
+		Qdcount: 1,
+		Ancount: 3,
+	}
+
+	packet.AddQuestion("google.com", 1, 1)
+	packet.AddAnswer("google.com", 1, 1, 60, 4, []byte{10, 0, 0, 1})
+	packet.AddAnswer("www.google.com", 1, 1, 60, 4, []byte{10, 0, 0, 2})
+	packet.AddAnswer("example.org", 1, 1, 60, 4, []byte{10, 0, 0, 3})
+
+	encoded := []byte{0, 1, 128, 0, 0, 1, 0, 3, 0, 0, 0, 0,
+		6, 103, 111, 111, 103, 108, 101, 3, 99, 111, 109, 0, 0, 1, 0, 1,
+		192, 12, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4, 10, 0, 0, 1,
+		3, 119, 119, 119, 192, 12, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4, 10, 0, 0, 2,
+		7, 101, 120, 97, 109, 112, 108, 101, 3, 111, 114, 103, 0, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4, 10, 0, 0, 3,
+	}
+
+	data, err := Encode(&packet)
+	if err != nil {
+		t.Fatalf("Failed.\nGot error: %s", err)
+	}
+
+	if !reflect.DeepEqual(data, encoded) {
+		t.Errorf("Failed. \nGot:  %v \nWant: %v \n", data, encoded)
+	}
+
+	decoded, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Fail.\nGot error: %s", err)
+	}
+
+	want := []Answer{
+		{Name: "google.com", Type: 1, Class: 1, TTL: 60, RdLength: 4, RData: &RecordTypeA{Addr: netip.MustParseAddr("10.0.0.1")}},
+		{Name: "www.google.com", Type: 1, Class: 1, TTL: 60, RdLength: 4, RData: &RecordTypeA{Addr: netip.MustParseAddr("10.0.0.2")}},
+		{Name: "example.org", Type: 1, Class: 1, TTL: 60, RdLength: 4, RData: &RecordTypeA{Addr: netip.MustParseAddr("10.0.0.3")}},
+	}
+
+	if !reflect.DeepEqual(decoded.Answers, want) {
+		t.Errorf("Fail.\nGot: %v\nWant: %v", decoded.Answers, want)
+	}
+}
+
+func TestEncodeCompressesAgainstAnswerNames(t *testing.T) {
+	packet := DNSPacket{
+		Header:  Header{ID: 1, QR: true},
+		Ancount: 2,
+	}
+
+	packet.Answers = []Answer{
+		{Name: "_http._tcp.local", Type: 1, Class: 1, TTL: 60, RdLength: 4, RData: &RecordTypeA{Addr: netip.MustParseAddr("10.0.0.1")}},
+		{Name: "host._tcp.local", Type: 1, Class: 1, TTL: 60, RdLength: 4, RData: &RecordTypeA{Addr: netip.MustParseAddr("10.0.0.2")}},
+	}
+
+	data, err := Encode(&packet)
+	if err != nil {
+		t.Fatalf("Failed.\nGot error: %s", err)
+	}
+	//4 host | pointer to _tcp.local at offset 18
+	second := []byte{4, 104, 111, 115, 116, 192, 18}
+
+	if !bytes.Equal(data[44:44+len(second)], second) {
+		t.Errorf("Failed. \nGot:  %v \nWant: %v \n", data[44:44+len(second)], second)
+	}
+
+	decoded, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Fail.\nGot error: %s", err)
+	}
+
+	if !reflect.DeepEqual(decoded.Answers, packet.Answers) {
+		t.Errorf("Fail.\nGot: %v\nWant: %v", decoded.Answers, packet.Answers)
+	}
+}
+
+func TestAuthorityAndAdditionalRoundTrip(t *testing.T) {
+	//a referral: NS record for example.org in authority, glue A record in additional
+	packet := DNSPacket{
+		Header:  Header{ID: 7, QR: true},
+		Qdcount: 1,
+		Nscount: 1,
+		Arcount: 1,
+	}
+
+	packet.AddQuestion("www.example.org", 1, 1)
+	//the name server is compressed to 2 ns | pointer to example.org, and the glue
+	//record's name is a pointer into the NS record
+	packet.Authority = []Answer{
+		{Name: "example.org", Type: 2, Class: 1, TTL: 3600, RdLength: 5, RData: &RecordTypeNS{Host: "ns.example.org"}},
+	}
+	packet.Additional = []Answer{
+		{Name: "ns.example.org", Type: 1, Class: 1, TTL: 3600, RdLength: 4, RData: &RecordTypeA{Addr: netip.MustParseAddr("192.0.2.1")}},
+	}
+
+	data, err := Encode(&packet)
+	if err != nil {
+		t.Fatalf("Failed.\nGot error: %s", err)
+	}
+
+	decoded, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Fail.\nGot error: %s", err)
+	}
+
+	if len(decoded.Answers) != 0 {
+		t.Errorf("Fail.\nGot answers: %v\nWant: none", decoded.Answers)
+	}
+
+	if !reflect.DeepEqual(decoded.Authority, packet.Authority) {
+		t.Errorf("Fail.\nGot: %v\nWant: %v", decoded.Authority, packet.Authority)
+	}
+
+	if !reflect.DeepEqual(decoded.Additional, packet.Additional) {
+		t.Errorf("Fail.\nGot: %v\nWant: %v", decoded.Additional, packet.Additional)
+	}
+}
+
+func TestDecodeMultipleMixedAnswers(t *testing.T) {
+	//service discovery reply: two SRV records followed by the A records of their targets.
+	//The second SRV target is compressed against the first owner name
+	data := []byte{0, 0, 132, 0, 0, 0, 0, 4, 0, 0, 0, 0,
+		5, 95, 104, 116, 116, 112, 4, 95, 116, 99, 112, 5, 108, 111, 99, 97, 108, 0, 0, 33, 0, 1, 0, 0, 0, 120, 0, 15, 0, 0, 0, 5, 31, 144, 1, 97, 5, 108, 111, 99, 97, 108, 0,
+		1, 98, 192, 12, 0, 33, 0, 1, 0, 0, 0, 120, 0, 10, 0, 1, 0, 10, 31, 145, 1, 98, 192, 23,
+		1, 97, 192, 23, 0, 1, 0, 1, 0, 0, 0, 120, 0, 4, 10, 0, 0, 1,
+		1, 98, 192, 23, 0, 1, 0, 1, 0, 0, 0, 120, 0, 4, 10, 0, 0, 2,
+	}
+
+	packet, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Fail.\nGot error: %s", err)
+	}
+
+	tables := []struct {
+		name  string
+		rdata PacketProcessor
+	}{
+		{"_http._tcp.local", &RecordTypeSRV{Priority: 0, Weight: 5, Port: 8080, Target: "a.local"}},
+		{"b._http._tcp.local", &RecordTypeSRV{Priority: 1, Weight: 10, Port: 8081, Target: "b.local"}},
+		{"a.local", &RecordTypeA{Addr: netip.MustParseAddr("10.0.0.1")}},
+		{"b.local", &RecordTypeA{Addr: netip.MustParseAddr("10.0.0.2")}},
+	}
+
+	if len(packet.Answers) != len(tables) {
+		t.Fatalf("Fail.\nGot: %d answers\nWant: %d", len(packet.Answers), len(tables))
+	}
+
+	for i, table := range tables {
+		answer := packet.Answers[i]
+
+		if answer.Name != table.name {
+			t.Errorf("Fail.\nGot: %s\nWant: %s", answer.Name, table.name)
+		}
+
+		if !reflect.DeepEqual(answer.RData, table.rdata) {
+			t.Errorf("Fail.\nGot: %v\nWant: %v", answer.RData, table.rdata)
+		}
+
+		if answer.Data != nil {
+			t.Errorf("Fail.\nGot raw data: %v\nWant: nil", answer.Data)
+		}
+	}
+}
+
+func TestRoundTripManyAnswers(t *testing.T) {
+	packet := DNSPacket{
+		Header:  Header{ID: 42, QR: true, AA: true},
+		Qdcount: 1,
+	}
+
+	packet.AddQuestion("_http._tcp.local", 1, DNSRecordTypeSRV)
+
+	for i := 0; i < 20; i++ {
+		host := fmt.Sprintf("host%d.local", i)
+		srv := &RecordTypeSRV{Priority: uint16(i), Weight: 1, Port: uint16(8000 + i), Target: host}
+		a := &RecordTypeA{Addr: netip.AddrFrom4([4]byte{10, 0, 0, byte(i)})}
+
+		packet.Answers = append(packet.Answers,
+			Answer{Name: "_http._tcp.local", Type: DNSRecordTypeSRV, Class: 1, TTL: 120, RdLength: 6 + len(encodeQname(host)), RData: srv},
+			Answer{Name: host, Type: DNSRecordTypeA, Class: 1, TTL: 120, RdLength: 4, RData: a},
+		)
+	}
+	packet.Ancount = uint16(len(packet.Answers))
+
+	data, err := Encode(&packet)
+	if err != nil {
+		t.Fatalf("Failed.\nGot error: %s", err)
+	}
+
+	decoded, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Fail.\nGot error: %s", err)
+	}
+
+	if !reflect.DeepEqual(decoded, &packet) {
+		t.Errorf("Fail.\nGot: \n%s\nWant: \n%s\n", decoded, packet)
+	}
+}
+
+func TestEncodeDerivesCounts(t *testing.T) {
+	packet := DNSPacket{
+		Header: Header{ID: 1},
+	}
+
+	packet.AddQuestion("google.com", 1, 1)
+	packet.AddAdditional("google.com", 1, 1, 60, 4, []byte{10, 0, 0, 1})
+
+	//the Add methods set the counts, Encode does not need them
+	packet.Qdcount = 0
+	packet.Arcount = 0
+
+	data, err := Encode(&packet)
+	if err != nil {
+		t.Fatalf("Failed.\nGot error: %s", err)
+	}
+
+	counts := []byte{0, 1, 0, 0, 0, 0, 0, 1}
+	if !bytes.Equal(data[4:12], counts) {
+		t.Errorf("Failed.\nGot:  %v\nWant: %v", data[4:12], counts)
+	}
+
+	_, err = EncodeStrict(&packet)
+	if !errors.Is(err, ErrCountMismatch) {
+		t.Errorf("Failed.\nGot: %v\nWant: %v", err, ErrCountMismatch)
+	}
+
+	packet.Qdcount = 1
+	packet.Arcount = 1
+
+	strict, err := EncodeStrict(&packet)
+	if err != nil {
+		t.Fatalf("Failed.\nGot error: %s", err)
+	}
+
+	if !bytes.Equal(strict, data) {
+		t.Errorf("Failed.\nGot:  %v\nWant: %v", strict, data)
+	}
+}
+
+func TestDecodeCountMismatch(t *testing.T) {
+	tables := [][]byte{
+		//two questions promised, one present
+		{0, 1, 1, 0, 0, 2, 0, 0, 0, 0, 0, 0, 3, 99, 111, 109, 0, 0, 1, 0, 1},
+		//one answer promised, none present
+		{0, 1, 129, 0, 0, 1, 0, 1, 0, 0, 0, 0, 3, 99, 111, 109, 0, 0, 1, 0, 1},
+		//one additional record promised, none present
+		{0, 1, 129, 0, 0, 0, 0, 0, 0, 0, 0, 1},
+	}
+
+	for _, table := range tables {
+		_, err := Decode(table)
+
+		if !errors.Is(err, ErrCountMismatch) {
+			t.Errorf("Fail.\nGot: %v\nWant: %v", err, ErrCountMismatch)
+		}
+	}
+}
+
+func TestUnknownTypeRoundTripsByteForByte(t *testing.T) {
+	//a TYPE65281 record whose data happens to look like a compression pointer
+	data := []byte{0, 9, 132, 0, 0, 1, 0, 1, 0, 0, 0, 0,
+		7, 101, 120, 97, 109, 112, 108, 101, 3, 111, 114, 103, 0, 255, 1, 0, 1,
+		192, 12, 255, 1, 0, 1, 0, 0, 14, 16, 0, 6, 1, 120, 192, 12, 0, 7,
+	}
+
+	packet, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Fail.\nGot error: %s", err)
+	}
+
+	want := &RecordTypeDefault{Code: 65281, Data: []byte{1, 120, 192, 12, 0, 7}}
+	if !reflect.DeepEqual(packet.Answers[0].RData, want) {
+		t.Errorf("Fail.\nGot: %v\nWant: %v", packet.Answers[0].RData, want)
+	}
+
+	if packet.Answers[0].RData.Type() != 65281 {
+		t.Errorf("Fail.\nGot: %d\nWant: %d", packet.Answers[0].RData.Type(), 65281)
+	}
+
+	encoded, err := Encode(packet)
+	if err != nil {
+		t.Fatalf("Fail.\nGot error: %s", err)
+	}
+
+	if !bytes.Equal(encoded, data) {
+		t.Errorf("Fail.\nGot:  %v\nWant: %v", encoded, data)
+	}
+}
@@ -0,0 +1,93 @@
+package dnsPacket
+
+import (
+	"fmt"
+)
+
+//moves the OPT record out of the additional section into dns.EDNS
+func (dns *DNSPacket) extractOPT() error {
+	additional := make([]Answer, 0, len(dns.Additional))
+
+	for _, a := range dns.Additional {
+		if a.Type != DNSRecordTypeOPT {
+			additional = append(additional, a)
+			continue
+		}
+
+		if dns.EDNS != nil {
+			return ErrMultipleOPT
+		}
+
+		if a.Name != "" {
+			return ErrBadOPT
+		}
+
+		opt := a.RData.(*RecordTypeOPT)
+		opt.fromAnswer(a)
+		dns.EDNS = opt
+	}
+
+	if len(additional) == 0 {
+		additional = nil
+	}
+	dns.Additional = additional
+
+	return nil
+}
+
+//returns the additional section as it is written, with the OPT record last
+func (dns *DNSPacket) additionalWithOPT() ([]Answer, error) {
+	if dns.EDNS == nil {
+		return dns.Additional, nil
+	}
+
+	for _, a := range dns.Additional {
+		if a.Type == DNSRecordTypeOPT {
+			return nil, fmt.Errorf("dnsPacket: OPT record in Additional while EDNS is set: %w", ErrMultipleOPT)
+		}
+	}
+
+	additional := make([]Answer, 0, len(dns.Additional)+1)
+	additional = append(additional, dns.Additional...)
+
+	return append(additional, dns.EDNS.answer()), nil
+}
+
+//ExtendedRcode returns the 12 bit response code made of the 4 header
+//bits and, when the packet has EDNS, the 8 bits in the OPT record.
+//An Rcode that does not fit in the header is returned as it is
+func (dns *DNSPacket) ExtendedRcode() Rcode {
+	if dns.Rcode > RcodeMask {
+		return dns.Rcode
+	}
+
+	rcode := dns.Rcode
+
+	if dns.EDNS != nil {
+		rcode = rcode | Rcode(dns.EDNS.ExtendedRcode)<<4
+	}
+
+	return rcode
+}
+
+//SetExtendedRcode splits rcode between the header and the OPT record.
+//Codes above 15 need EDNS, if the packet has none it is added with the
+//default UDP payload size. Codes longer than 12 bits cannot be written:
+//they are kept whole in Rcode, so Encode and Validate reject the packet
+//with ErrBadRcode instead of sending another code
+func (dns *DNSPacket) SetExtendedRcode(rcode Rcode) {
+	if rcode > maxExtendedRcode {
+		dns.Rcode = rcode
+		return
+	}
+
+	dns.Rcode = rcode & RcodeMask
+
+	if rcode > RcodeMask && dns.EDNS == nil {
+		dns.EDNS = NewOPT(DefaultUDPSize)
+	}
+
+	if dns.EDNS != nil {
+		dns.EDNS.ExtendedRcode = uint8(rcode >> 4)
+	}
+}
@@ -0,0 +1,46 @@
+package dnsPacket
+
+import (
+	"fmt"
+)
+
+//EDNSOptionCookie is a DNS Cookie (RFC 7873). Clients send an 8 byte
+//client cookie, servers answer with it and a server cookie of 8 to 32 bytes
+type EDNSOptionCookie struct {
+	Client [8]byte
+	Server []byte
+}
+
+func (option *EDNSOptionCookie) Code() int {
+	return EDNSOptionCodeCookie
+}
+
+func (option *EDNSOptionCookie) Decode(data []byte) error {
+	if len(data) != 8 && (len(data) < 16 || len(data) > 40) {
+		return ErrBadEDNSOption
+	}
+
+	copy(option.Client[:], data[:8])
+	option.Server = nil
+	if len(data) > 8 {
+		option.Server = make([]byte, len(data)-8)
+		copy(option.Server, data[8:])
+	}
+
+	return nil
+}
+
+func (option *EDNSOptionCookie) Encode() ([]byte, error) {
+	if len(option.Server) != 0 && (len(option.Server) < 8 || len(option.Server) > 32) {
+		return nil, fmt.Errorf("dnsPacket: server cookie of %d bytes: %w", len(option.Server), ErrBadEDNSOption)
+	}
+
+	data := make([]byte, 0, 8+len(option.Server))
+	data = append(data, option.Client[:]...)
+
+	return append(data, option.Server...), nil
+}
+
+func (option *EDNSOptionCookie) String() string {
+	return fmt.Sprintf("%x%x", option.Client, option.Server)
+}
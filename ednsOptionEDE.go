@@ -0,0 +1,114 @@
+package dnsPacket
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+//EDNSOptionEDE is an Extended DNS Error (RFC 8914). It explains why a
+//server answered the way it did, on top of the rcode
+type EDNSOptionEDE struct {
+	InfoCode  uint16
+	ExtraText string
+}
+
+//Extended DNS Error info codes
+const (
+	EDEOther                      = 0
+	EDEUnsupportedDNSKEYAlgorithm = 1
+	EDEUnsupportedDSDigestType    = 2
+	EDEStaleAnswer                = 3
+	EDEForgedAnswer               = 4
+	EDEDNSSECIndeterminate        = 5
+	EDEDNSSECBogus                = 6
+	EDESignatureExpired           = 7
+	EDESignatureNotYetValid       = 8
+	EDEDNSKEYMissing              = 9
+	EDERRSIGsMissing              = 10
+	EDENoZoneKeyBitSet            = 11
+	EDENSECMissing                = 12
+	EDECachedError                = 13
+	EDENotReady                   = 14
+	EDEBlocked                    = 15
+	EDECensored                   = 16
+	EDEFiltered                   = 17
+	EDEProhibited                 = 18
+	EDEStaleNXDomainAnswer        = 19
+	EDENotAuthoritative           = 20
+	EDENotSupported               = 21
+	EDENoReachableAuthority       = 22
+	EDENetworkError               = 23
+	EDEInvalidData                = 24
+)
+
+var edeInfoCodeNames = map[uint16]string{
+	EDEOther:                      "Other Error",
+	EDEUnsupportedDNSKEYAlgorithm: "Unsupported DNSKEY Algorithm",
+	EDEUnsupportedDSDigestType:    "Unsupported DS Digest Type",
+	EDEStaleAnswer:                "Stale Answer",
+	EDEForgedAnswer:               "Forged Answer",
+	EDEDNSSECIndeterminate:        "DNSSEC Indeterminate",
+	EDEDNSSECBogus:                "DNSSEC Bogus",
+	EDESignatureExpired:           "Signature Expired",
+	EDESignatureNotYetValid:       "Signature Not Yet Valid",
+	EDEDNSKEYMissing:              "DNSKEY Missing",
+	EDERRSIGsMissing:              "RRSIGs Missing",
+	EDENoZoneKeyBitSet:            "No Zone Key Bit Set",
+	EDENSECMissing:                "NSEC Missing",
+	EDECachedError:                "Cached Error",
+	EDENotReady:                   "Not Ready",
+	EDEBlocked:                    "Blocked",
+	EDECensored:                   "Censored",
+	EDEFiltered:                   "Filtered",
+	EDEProhibited:                 "Prohibited",
+	EDEStaleNXDomainAnswer:        "Stale NXDomain Answer",
+	EDENotAuthoritative:           "Not Authoritative",
+	EDENotSupported:               "Not Supported",
+	EDENoReachableAuthority:       "No Reachable Authority",
+	EDENetworkError:               "Network Error",
+	EDEInvalidData:                "Invalid Data",
+}
+
+func (option *EDNSOptionEDE) Code() int {
+	return EDNSOptionCodeEDE
+}
+
+// 2 bytes   | rest of the option
+//INFO-CODE  | EXTRA-TEXT (UTF-8, not NUL terminated)
+func (option *EDNSOptionEDE) Decode(data []byte) error {
+	if len(data) < 2 || !utf8.Valid(data[2:]) {
+		return ErrBadEDNSOption
+	}
+
+	option.InfoCode = decodePart(data, 0, 2)
+	option.ExtraText = string(data[2:])
+
+	return nil
+}
+
+func (option *EDNSOptionEDE) Encode() ([]byte, error) {
+	if !utf8.ValidString(option.ExtraText) {
+		return nil, fmt.Errorf("dnsPacket: extended error text is not UTF-8: %w", ErrBadEDNSOption)
+	}
+
+	data, _ := fromIntToBytes(option.InfoCode)
+
+	return append(data, option.ExtraText...), nil
+}
+
+//InfoCodeName returns the name RFC 8914 gives the info code
+func (option *EDNSOptionEDE) InfoCodeName() string {
+	if name, ok := edeInfoCodeNames[option.InfoCode]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Info Code %d", option.InfoCode)
+}
+
+func (option *EDNSOptionEDE) String() string {
+	if option.ExtraText == "" {
+		return fmt.Sprintf("%d (%s)", option.InfoCode, option.InfoCodeName())
+	}
+
+	return fmt.Sprintf("%d (%s): %s", option.InfoCode, option.InfoCodeName(), option.ExtraText)
+}
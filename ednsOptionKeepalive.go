@@ -0,0 +1,55 @@
+package dnsPacket
+
+import (
+	"time"
+)
+
+//EDNSOptionKeepalive is the edns-tcp-keepalive option (RFC 7828).
+//Clients send it without a timeout, servers answer with how long they
+//keep idle TCP connections open, in units of 100 milliseconds
+type EDNSOptionKeepalive struct {
+	Timeout    uint16
+	HasTimeout bool
+}
+
+func (option *EDNSOptionKeepalive) Code() int {
+	return EDNSOptionCodeKeepalive
+}
+
+func (option *EDNSOptionKeepalive) Decode(data []byte) error {
+	switch len(data) {
+	case 0:
+		option.Timeout = 0
+		option.HasTimeout = false
+
+	case 2:
+		option.Timeout = decodePart(data, 0, 2)
+		option.HasTimeout = true
+
+	default:
+		return ErrBadEDNSOption
+	}
+
+	return nil
+}
+
+func (option *EDNSOptionKeepalive) Encode() ([]byte, error) {
+	if !option.HasTimeout {
+		return []byte{}, nil
+	}
+
+	return fromIntToBytes(option.Timeout)
+}
+
+//Duration returns the timeout as a time.Duration
+func (option *EDNSOptionKeepalive) Duration() time.Duration {
+	return time.Duration(option.Timeout) * 100 * time.Millisecond
+}
+
+func (option *EDNSOptionKeepalive) String() string {
+	if !option.HasTimeout {
+		return "(no timeout)"
+	}
+
+	return option.Duration().String()
+}
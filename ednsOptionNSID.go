@@ -0,0 +1,36 @@
+package dnsPacket
+
+import (
+	"fmt"
+)
+
+//EDNSOptionNSID is the Name Server Identifier option (RFC 5001).
+//Queries carry it empty, servers fill in an identifier of their choosing
+type EDNSOptionNSID struct {
+	ID []byte
+}
+
+func (option *EDNSOptionNSID) Code() int {
+	return EDNSOptionCodeNSID
+}
+
+func (option *EDNSOptionNSID) Decode(data []byte) error {
+	option.ID = make([]byte, len(data))
+	copy(option.ID, data)
+
+	return nil
+}
+
+func (option *EDNSOptionNSID) Encode() ([]byte, error) {
+	return option.ID, nil
+}
+
+//the identifier in hex, followed by its text when it is printable
+func (option *EDNSOptionNSID) String() string {
+	text := string(option.ID)
+	if len(text) == 0 || escapeText(text, `"\`) != text {
+		return fmt.Sprintf("%x", option.ID)
+	}
+
+	return fmt.Sprintf("%x (%q)", option.ID, text)
+}
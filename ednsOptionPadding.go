@@ -0,0 +1,33 @@
+package dnsPacket
+
+import (
+	"fmt"
+)
+
+//EDNSOptionPadding pads a message to hide its length on encrypted
+//transports (RFC 7830). It is written as Length zero bytes
+type EDNSOptionPadding struct {
+	Length int
+}
+
+func (option *EDNSOptionPadding) Code() int {
+	return EDNSOptionCodePadding
+}
+
+func (option *EDNSOptionPadding) Decode(data []byte) error {
+	option.Length = len(data)
+
+	return nil
+}
+
+func (option *EDNSOptionPadding) Encode() ([]byte, error) {
+	if option.Length < 0 {
+		return nil, ErrBadEDNSOption
+	}
+
+	return make([]byte, option.Length), nil
+}
+
+func (option *EDNSOptionPadding) String() string {
+	return fmt.Sprintf("(%d bytes)", option.Length)
+}
@@ -0,0 +1,91 @@
+package dnsPacket
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+//EDNSOptionSubnet is the EDNS Client Subnet option (RFC 7871). It tells
+//the server which network the query originates from.
+//Address is masked to SourcePrefix bits when encoding
+type EDNSOptionSubnet struct {
+	SourcePrefix uint8
+	ScopePrefix  uint8
+	Address      netip.Addr
+}
+
+// 2 bytes | 1 byte               | 1 byte              | ceil(SOURCE PREFIX / 8) bytes
+//FAMILY   | SOURCE PREFIX-LENGTH | SCOPE PREFIX-LENGTH | ADDRESS
+
+const (
+	subnetFamilyIPv4 = 1
+	subnetFamilyIPv6 = 2
+)
+
+func (option *EDNSOptionSubnet) Code() int {
+	return EDNSOptionCodeSubnet
+}
+
+func (option *EDNSOptionSubnet) Decode(data []byte) error {
+	if len(data) < 4 {
+		return ErrBadEDNSOption
+	}
+
+	family := decodePart(data, 0, 2)
+	source := int(data[2])
+	scope := int(data[3])
+	address := data[4:]
+
+	var bits int
+	switch family {
+	case subnetFamilyIPv4:
+		bits = 32
+	case subnetFamilyIPv6:
+		bits = 128
+	default:
+		return ErrBadEDNSOption
+	}
+
+	if source > bits || scope > bits || len(address) != (source+7)/8 {
+		return ErrBadEDNSOption
+	}
+
+	full := make([]byte, bits/8)
+	copy(full, address)
+	addr, _ := netip.AddrFromSlice(full)
+
+	//bits past the source prefix must be zero
+	if prefix, _ := addr.Prefix(source); prefix.Addr() != addr {
+		return ErrBadEDNSOption
+	}
+
+	option.SourcePrefix = uint8(source)
+	option.ScopePrefix = uint8(scope)
+	option.Address = addr
+
+	return nil
+}
+
+func (option *EDNSOptionSubnet) Encode() ([]byte, error) {
+	addr := option.Address
+	family := subnetFamilyIPv6
+	if addr.Is4() {
+		family = subnetFamilyIPv4
+	}
+
+	prefix, err := addr.Prefix(int(option.SourcePrefix))
+	if !addr.IsValid() || err != nil || int(option.ScopePrefix) > addr.BitLen() {
+		return nil, fmt.Errorf("dnsPacket: client subnet %s/%d scope %d: %w", addr, option.SourcePrefix, option.ScopePrefix, ErrBadEDNSOption)
+	}
+
+	familyBytes, _ := fromIntToBytes(uint16(family))
+	data := append(familyBytes, option.SourcePrefix, option.ScopePrefix)
+	data = append(data, prefix.Addr().AsSlice()[:(option.SourcePrefix+7)/8]...)
+
+	return data, nil
+}
+
+//written as address/source/scope, like dig does
+func (option *EDNSOptionSubnet) String() string {
+	return fmt.Sprintf("%s/%d/%d", option.Address, option.SourcePrefix, option.ScopePrefix)
+}
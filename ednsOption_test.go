@@ -0,0 +1,139 @@
+package dnsPacket
+
+import (
+	"bytes"
+	"errors"
+	"net/netip"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEDNSOptionsRoundTrip(t *testing.T) {
+	tables := []struct {
+		option EDNSOption
+		data   []byte
+	}{
+		{&EDNSOptionNSID{ID: []byte("ns1")}, []byte{0, 3, 0, 3, 110, 115, 49}},
+		{&EDNSOptionSubnet{SourcePrefix: 24, Address: netip.MustParseAddr("192.0.2.0")}, []byte{0, 8, 0, 7, 0, 1, 24, 0, 192, 0, 2}},
+		{&EDNSOptionSubnet{SourcePrefix: 56, ScopePrefix: 48, Address: netip.MustParseAddr("2001:db8:1:2200::")}, []byte{0, 8, 0, 11, 0, 2, 56, 48, 32, 1, 13, 184, 0, 1, 34}},
+		{&EDNSOptionSubnet{SourcePrefix: 0, Address: netip.MustParseAddr("0.0.0.0")}, []byte{0, 8, 0, 4, 0, 1, 0, 0}},
+		{&EDNSOptionCookie{Client: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}}, []byte{0, 10, 0, 8, 1, 2, 3, 4, 5, 6, 7, 8}},
+		{&EDNSOptionCookie{Client: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}, Server: []byte{9, 9, 9, 9, 9, 9, 9, 9}}, []byte{0, 10, 0, 16, 1, 2, 3, 4, 5, 6, 7, 8, 9, 9, 9, 9, 9, 9, 9, 9}},
+		{&EDNSOptionKeepalive{}, []byte{0, 11, 0, 0}},
+		{&EDNSOptionKeepalive{Timeout: 300, HasTimeout: true}, []byte{0, 11, 0, 2, 1, 44}},
+		{&EDNSOptionPadding{Length: 3}, []byte{0, 12, 0, 3, 0, 0, 0}},
+		{&EDNSOptionEDE{InfoCode: EDEBlocked, ExtraText: "ads"}, []byte{0, 15, 0, 5, 0, 15, 97, 100, 115}},
+		{&EDNSOptionDefault{OptionCode: 65001, Data: []byte{1, 2, 3}}, []byte{253, 233, 0, 3, 1, 2, 3}},
+	}
+
+	for _, table := range tables {
+		opt := RecordTypeOPT{Options: []EDNSOption{table.option}}
+
+		data, err := opt.Encode(nil, nil)
+		if err != nil {
+			t.Fatalf("Fail\nGot error: %s", err)
+		}
+
+		if !bytes.Equal(data, table.data) {
+			t.Errorf("Fail\nGot: %v\nWant: %v", data, table.data)
+		}
+
+		decoded := RecordTypeOPT{}
+		if err := decoded.Decode(data, 0, len(data)); err != nil {
+			t.Fatalf("Fail\nGot error: %s", err)
+		}
+
+		if !reflect.DeepEqual(decoded.Options[0], table.option) {
+			t.Errorf("Fail\nGot: %v\nWant: %v", decoded.Options[0], table.option)
+		}
+	}
+}
+
+func TestEDNSOptionsMasking(t *testing.T) {
+	//bits past the source prefix are dropped when encoding
+	option := EDNSOptionSubnet{SourcePrefix: 24, Address: netip.MustParseAddr("192.0.2.55")}
+
+	data, err := option.Encode()
+	if err != nil || !bytes.Equal(data, []byte{0, 1, 24, 0, 192, 0, 2}) {
+		t.Errorf("Fail\nGot: %v %v\nWant: %v", data, err, []byte{0, 1, 24, 0, 192, 0, 2})
+	}
+
+	keepalive := EDNSOptionKeepalive{Timeout: 25, HasTimeout: true}
+	if keepalive.Duration() != 2500*time.Millisecond {
+		t.Errorf("Fail\nGot: %s\nWant: %s", keepalive.Duration(), 2500*time.Millisecond)
+	}
+
+	ede := EDNSOptionEDE{InfoCode: EDEStaleAnswer}
+	if name := ede.InfoCodeName(); name != "Stale Answer" {
+		t.Errorf("Fail\nGot: %s\nWant: %s", name, "Stale Answer")
+	}
+}
+
+func TestEDNSOptionErrors(t *testing.T) {
+	tables := []struct {
+		option EDNSOption
+		data   []byte
+	}{
+		//address longer than the source prefix needs
+		{&EDNSOptionSubnet{}, []byte{0, 1, 8, 0, 192, 0}},
+		//bits set past the source prefix
+		{&EDNSOptionSubnet{}, []byte{0, 1, 7, 0, 193}},
+		//unknown family
+		{&EDNSOptionSubnet{}, []byte{0, 3, 0, 0}},
+		{&EDNSOptionSubnet{}, []byte{0, 1, 33, 0, 1, 2, 3, 4, 5}},
+		{&EDNSOptionCookie{}, []byte{1, 2, 3}},
+		{&EDNSOptionCookie{}, []byte{1, 2, 3, 4, 5, 6, 7, 8, 9}},
+		{&EDNSOptionKeepalive{}, []byte{1}},
+		{&EDNSOptionEDE{}, []byte{0}},
+		{&EDNSOptionEDE{}, []byte{0, 1, 255}},
+	}
+
+	for _, table := range tables {
+		if err := table.option.Decode(table.data); err != ErrBadEDNSOption {
+			t.Errorf("Fail %v\nGot: %v\nWant: %v", table.data, err, ErrBadEDNSOption)
+		}
+	}
+
+	invalid := []EDNSOption{
+		&EDNSOptionSubnet{SourcePrefix: 33, Address: netip.MustParseAddr("192.0.2.0")},
+		&EDNSOptionSubnet{SourcePrefix: 24},
+		&EDNSOptionCookie{Server: []byte{1}},
+		&EDNSOptionEDE{ExtraText: "\xff"},
+	}
+
+	for _, option := range invalid {
+		if _, err := option.Encode(); !errors.Is(err, ErrBadEDNSOption) {
+			t.Errorf("Fail %v\nGot: %v\nWant: %v", option, err, ErrBadEDNSOption)
+		}
+	}
+}
+
+func TestEDNSOptionsString(t *testing.T) {
+	opt := NewOPT(4096)
+	opt.DO = true
+	opt.Options = []EDNSOption{
+		&EDNSOptionSubnet{SourcePrefix: 24, Address: netip.MustParseAddr("192.0.2.0")},
+		&EDNSOptionCookie{Client: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}, Server: []byte{9, 10, 11, 12, 13, 14, 15, 16}},
+		&EDNSOptionNSID{ID: []byte{0, 1}},
+		&EDNSOptionKeepalive{Timeout: 300, HasTimeout: true},
+		&EDNSOptionKeepalive{},
+		&EDNSOptionPadding{Length: 12},
+		&EDNSOptionEDE{InfoCode: EDEStaleAnswer},
+		&EDNSOptionDefault{OptionCode: 65001, Data: []byte{0xab}},
+	}
+
+	want := `; EDNS: version: 0, flags: do; udp: 4096
+; CLIENT-SUBNET: 192.0.2.0/24/0
+; COOKIE: 0102030405060708090a0b0c0d0e0f10
+; NSID: 0001
+; TCP-KEEPALIVE: 30s
+; TCP-KEEPALIVE: (no timeout)
+; PADDING: (12 bytes)
+; EDE: 3 (Stale Answer)
+; OPT=65001: ab`
+
+	if out := opt.String(); out != want {
+		t.Errorf("Fail\nGot:\n%s\nWant:\n%s", out, want)
+	}
+}
@@ -0,0 +1,171 @@
+package dnsPacket
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeEDNS(t *testing.T) {
+	//query for example.org with OPT: udp 4096, extended rcode 1, version 0, DO set, one option 65001
+	data := []byte{0, 1, 1, 0, 0, 1, 0, 0, 0, 0, 0, 1,
+		7, 101, 120, 97, 109, 112, 108, 101, 3, 111, 114, 103, 0, 0, 1, 0, 1,
+		0, 0, 41, 16, 0, 1, 0, 128, 0, 0, 6, 253, 233, 0, 2, 1, 2,
+	}
+
+	packet, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
+	want := &RecordTypeOPT{
+		UDPSize:       4096,
+		ExtendedRcode: 1,
+		DO:            true,
+		Options:       []EDNSOption{&EDNSOptionDefault{OptionCode: 65001, Data: []byte{1, 2}}},
+	}
+
+	if !reflect.DeepEqual(packet.EDNS, want) {
+		t.Errorf("Fail\nGot: %v\nWant: %v", packet.EDNS, want)
+	}
+
+	if len(packet.Additional) != 0 {
+		t.Errorf("Fail\nGot: %v\nWant: OPT taken out of the additional section", packet.Additional)
+	}
+
+	if rcode := packet.ExtendedRcode(); rcode != RcodeBadVersion {
+		t.Errorf("Fail\nGot: %d\nWant: %d", rcode, RcodeBadVersion)
+	}
+
+	encoded, err := EncodeStrict(packet)
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
+	if !bytes.Equal(encoded, data) {
+		t.Errorf("Fail\nGot:  %v\nWant: %v", encoded, data)
+	}
+}
+
+func TestDecodeMalformedEDNSOption(t *testing.T) {
+	tables := []struct {
+		name   string
+		option []byte
+	}{
+		//client subnet 192.0.2.1/24: bits set past the source prefix
+		{"client subnet", []byte{0, 8, 0, 8, 0, 1, 24, 0, 192, 0, 2, 1}},
+		//extended error with text that is not UTF-8
+		{"extended error", []byte{0, 15, 0, 4, 0, 3, 0xc3, 0x28}},
+	}
+
+	for _, table := range tables {
+		//query for example.org with OPT holding the option and a cookie after it
+		data := []byte{0, 1, 1, 0, 0, 1, 0, 0, 0, 0, 0, 1,
+			7, 101, 120, 97, 109, 112, 108, 101, 3, 111, 114, 103, 0, 0, 1, 0, 1,
+			0, 0, 41, 16, 0, 0, 0, 0, 0, 0, byte(len(table.option) + 12),
+		}
+		data = append(data, table.option...)
+		data = append(data, 0, 10, 0, 8, 1, 2, 3, 4, 5, 6, 7, 8)
+
+		packet, err := Decode(data)
+		if err != nil {
+			t.Fatalf("%s: Fail\nGot error: %s", table.name, err)
+		}
+
+		want := []EDNSOption{
+			&EDNSOptionDefault{OptionCode: int(table.option[1]), Data: table.option[4:]},
+			&EDNSOptionCookie{Client: [8]byte{1, 2, 3, 4, 5, 6, 7, 8}},
+		}
+
+		if !reflect.DeepEqual(packet.EDNS.Options, want) {
+			t.Errorf("%s: Fail\nGot: %v\nWant: %v", table.name, packet.EDNS.Options, want)
+		}
+
+		encoded, err := EncodeStrict(packet)
+		if err != nil || !bytes.Equal(encoded, data) {
+			t.Errorf("%s: Fail\nGot:  %v %v\nWant: %v", table.name, encoded, err, data)
+		}
+	}
+}
+
+func TestEncodePlacesOPTLast(t *testing.T) {
+	packet := DNSPacket{
+		Header: Header{ID: 1, QR: true},
+		EDNS:   NewOPT(DefaultUDPSize),
+	}
+
+	packet.AddQuestion("example.org", QclassIN, DNSRecordTypeTXT)
+	packet.Additional = []Answer{{Name: "example.org", Type: DNSRecordTypeTXT, Class: QclassIN, TTL: 60, RData: &RecordTypeTXT{Text: []string{"x"}}}}
+
+	data, err := Encode(&packet)
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
+	opt := []byte{0, 0, 41, 4, 208, 0, 0, 0, 0, 0, 0}
+	if !bytes.Equal(data[len(data)-len(opt):], opt) || data[11] != 2 {
+		t.Errorf("Fail\nGot: %v\nWant: arcount 2 and OPT record %v last", data, opt)
+	}
+
+	packet.Additional = append(packet.Additional, NewOPT(512).answer())
+	if _, err := Encode(&packet); !errors.Is(err, ErrMultipleOPT) {
+		t.Errorf("Fail\nGot: %v\nWant: %v", err, ErrMultipleOPT)
+	}
+}
+
+func TestDecodeMultipleOPT(t *testing.T) {
+	data := []byte{0, 1, 1, 0, 0, 0, 0, 0, 0, 0, 0, 2,
+		0, 0, 41, 2, 0, 0, 0, 0, 0, 0, 0,
+		0, 0, 41, 2, 0, 0, 0, 0, 0, 0, 0,
+	}
+
+	if _, err := Decode(data); !errors.Is(err, ErrMultipleOPT) {
+		t.Errorf("Fail\nGot: %v\nWant: %v", err, ErrMultipleOPT)
+	}
+}
+
+func TestSetExtendedRcode(t *testing.T) {
+	packet := DNSPacket{Header: Header{QR: true}}
+
+	packet.SetExtendedRcode(RcodeNameError)
+	if packet.EDNS != nil || packet.Rcode != RcodeNameError {
+		t.Errorf("Fail\nGot: %d %v\nWant: %d without EDNS", packet.Rcode, packet.EDNS, RcodeNameError)
+	}
+
+	packet.SetExtendedRcode(RcodeBadCookie)
+	if packet.EDNS == nil || packet.Rcode != 7 || packet.EDNS.ExtendedRcode != 1 {
+		t.Fatalf("Fail\nGot: %d %v\nWant: header 7, OPT 1", packet.Rcode, packet.EDNS)
+	}
+
+	if rcode := packet.ExtendedRcode(); rcode != RcodeBadCookie {
+		t.Errorf("Fail\nGot: %d\nWant: %d", rcode, RcodeBadCookie)
+	}
+
+	if size := packet.EDNS.PayloadSize(); size != DefaultUDPSize {
+		t.Errorf("Fail\nGot: %d\nWant: %d", size, DefaultUDPSize)
+	}
+
+	if size := NewOPT(100).PayloadSize(); size != 512 {
+		t.Errorf("Fail\nGot: %d\nWant: %d", size, 512)
+	}
+
+	//4099 does not fit in 12 bits, cut down it would be NXDOMAIN
+	packet.SetExtendedRcode(4099)
+	if rcode := packet.ExtendedRcode(); rcode != 4099 || packet.IsNXDomain() {
+		t.Errorf("Fail\nGot: %d %t\nWant: 4099, not NXDOMAIN", rcode, packet.IsNXDomain())
+	}
+
+	if _, err := Encode(&packet); !errors.Is(err, ErrBadRcode) {
+		t.Errorf("Fail\nGot: %v\nWant: %v", err, ErrBadRcode)
+	}
+
+	if err := packet.Validate(); !errors.Is(err, ErrBadRcode) {
+		t.Errorf("Fail\nGot: %v\nWant: %v", err, ErrBadRcode)
+	}
+
+	packet.SetExtendedRcode(maxExtendedRcode)
+	if rcode := packet.ExtendedRcode(); rcode != maxExtendedRcode || packet.Rcode != RcodeMask || packet.EDNS.ExtendedRcode != 0xFF {
+		t.Errorf("Fail\nGot: %d header %d OPT %d\nWant: %d", rcode, packet.Rcode, packet.EDNS.ExtendedRcode, maxExtendedRcode)
+	}
+}
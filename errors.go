@@ -0,0 +1,113 @@
+package dnsPacket
+
+import (
+	"errors"
+	"fmt"
+)
+
+//Errors returned (wrapped in a DecodeError) when a packet is malformed.
+//ErrCountMismatch is also returned by EncodeStrict, ErrNameTooLong by
+//Encode and Validate. ErrBadEDNSOption is only returned by the Decode and
+//Encode methods of the options, a packet keeps a malformed option as an
+//EDNSOptionDefault
+var (
+	ErrShortHeader     = errors.New("packet is shorter than the 12 byte header")
+	ErrShortBuffer     = errors.New("packet ends in the middle of a record")
+	ErrLabelOverflow   = errors.New("label length runs past the end of the packet")
+	ErrBadRdLength     = errors.New("rdlength runs past the end of the packet")
+	ErrTrailingData    = errors.New("trailing data after the last record")
+	ErrBadLabelType    = errors.New("reserved label type")
+	ErrBadPointer      = errors.New("compression pointer points past the end of the packet")
+	ErrPointerLoop     = errors.New("compression pointers form a loop")
+	ErrTooManyPointers = errors.New("name follows too many compression pointers")
+	ErrNameTooLong     = errors.New("name is longer than 255 octets")
+	ErrCountMismatch   = errors.New("section count does not match the records in the section")
+	ErrBadRdata        = errors.New("rdata does not match its record type")
+	ErrMultipleOPT     = errors.New("more than one OPT record")
+	ErrBadOPT          = errors.New("OPT record is not owned by the root name")
+	ErrBadEDNSOption   = errors.New("EDNS option does not match its code")
+)
+
+//Errors returned by Encode and Validate
+var (
+	ErrSectionTooLong         = errors.New("section holds more than 65535 entries")
+	ErrRdataTooLong           = errors.New("rdata is longer than 65535 bytes")
+	ErrCharacterStringTooLong = errors.New("character-string is longer than 255 bytes")
+	ErrLabelTooLong           = errors.New("label is longer than 63 octets")
+	ErrEmptyLabel             = errors.New("name holds an empty label")
+	ErrBadEscape              = errors.New("name holds a malformed escape")
+	ErrBadOpcode              = errors.New("opcode does not fit in 4 bits")
+	ErrBadRcode               = errors.New("rcode does not fit in the header, use SetExtendedRcode")
+)
+
+//Errors only returned by Validate. Encode still writes these packets,
+//so that anything Decode accepts can be encoded again
+var (
+	ErrReservedBit      = errors.New("reserved Z bit is set")
+	ErrMissingQuestion  = errors.New("response to a query holds no question")
+	ErrTooManyQuestions = errors.New("query holds more than one question")
+)
+
+//Errors returned (wrapped in a ParseError) when a record in presentation
+//format is malformed. ErrBadRdata and the name errors above are used too
+var (
+	ErrSyntax               = errors.New("unbalanced quotes or parentheses, or a missing field")
+	ErrBadTTL               = errors.New("TTL is not a number of seconds or a duration")
+	ErrUnknownType          = errors.New("unknown record type")
+	ErrNoPresentationFormat = errors.New("record type can only be written in the \\# generic form")
+	ErrNoOwner              = errors.New("first record of the zone has no owner name")
+	ErrNoTTL                = errors.New("record has no TTL and no $TTL is set")
+	ErrBadDirective         = errors.New("unknown or malformed $ directive")
+	ErrIncludeDepth         = errors.New("$INCLUDE nested too deep")
+)
+
+//Errors returned by ToASCII, ToUnicode and NewIDNQuery, together with the
+//name errors of Encode
+var (
+	ErrBadIDN = errors.New("label is not a valid internationalized label")
+)
+
+//Errors returned by Client.Exchange, besides network errors and the
+//error of the context
+var (
+	ErrNoResponse = errors.New("no matching response from the server")
+)
+
+//DecodeError is returned by Decode. It records where in the packet
+//decoding stopped and why. Use errors.Is to compare Err against the
+//ErrXXX values above.
+type DecodeError struct {
+	Offset int
+	Err    error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("dnsPacket: decode failed at offset %d: %s", e.Offset, e.Err)
+}
+
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+//ParseError is returned by ParseRR and ZoneParser. It records the text
+//that could not be parsed and why, and for zone files the file and line
+//(counting from 1) the record starts on. Use errors.Is to compare Err
+//against the ErrXXX values above
+type ParseError struct {
+	File  string
+	Line  int
+	Token string
+	Err   error
+}
+
+func (e *ParseError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("dnsPacket: %s:%d: parse failed at %q: %s", e.File, e.Line, e.Token, e.Err)
+	}
+
+	return fmt.Sprintf("dnsPacket: parse failed at %q: %s", e.Token, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,248 @@
+package dnsPacket
+
+import (
+	"strconv"
+	"strings"
+)
+
+//$GENERATE (BIND)
+//
+//  $GENERATE <start>-<stop>[/<step>] <lhs> [<ttl>] [<class>] <type> <rhs>
+//
+//writes one record for every value from start to stop. In lhs and rhs a
+//$ is replaced by the value and ${offset,width,base} by the value plus
+//offset, zero padded to width and written in base d (decimal), o (octal),
+//x or X (hex) or n or N (reversed nibbles, as in ip6.arpa names). \$ is
+//a literal dollar sign
+//
+//  $GENERATE 1-254 ${0,3,d} PTR host$.example.org.
+
+//the largest value $GENERATE counts to
+const maxGenerateValue = 1<<31 - 1
+
+//the records of a $GENERATE still to be written
+type generator struct {
+	current int
+	stop    int
+	step    int
+	owner   string  //lhs
+	tokens  []token //ttl, class, type and rhs, not expanded yet
+	file    *zoneFile
+	line    int
+}
+
+//reads the arguments of $GENERATE
+func newGenerator(file *zoneFile, line int, args []token) (*generator, error) {
+	if len(args) < 3 {
+		return nil, ErrBadDirective
+	}
+
+	bounds, step, hasStep := strings.Cut(args[0].text, "/")
+	startText, stopText, ok := strings.Cut(bounds, "-")
+	if !ok {
+		return nil, ErrBadDirective
+	}
+
+	start, err := strconv.ParseUint(startText, 10, 31)
+	if err != nil {
+		return nil, ErrBadDirective
+	}
+
+	stop, err := strconv.ParseUint(stopText, 10, 31)
+	if err != nil || stop < start {
+		return nil, ErrBadDirective
+	}
+
+	g := &generator{current: int(start), stop: int(stop), step: 1, owner: args[1].text, tokens: args[2:], file: file, line: line}
+
+	if hasStep {
+		value, err := strconv.ParseUint(step, 10, 31)
+		if err != nil || value == 0 {
+			return nil, ErrBadDirective
+		}
+
+		g.step = int(value)
+	}
+
+	//check the templates once rather than for every record
+	for _, template := range append([]string{g.owner}, tokenTexts(g.tokens)...) {
+		if _, err := expand(template, g.current); err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+//returns the tokens of the next record, or false once stop is passed
+func (g *generator) next() ([]token, bool, error) {
+	if g.current > g.stop {
+		return nil, false, nil
+	}
+
+	value := g.current
+	g.current = g.current + g.step
+
+	owner, err := expand(g.owner, value)
+	if err != nil {
+		return nil, false, err
+	}
+
+	tokens := []token{{text: owner}}
+	for _, t := range g.tokens {
+		text, err := expand(t.text, value)
+		if err != nil {
+			return nil, false, err
+		}
+
+		tokens = append(tokens, token{text: text, quoted: t.quoted})
+	}
+
+	return tokens, true, nil
+}
+
+//replaces the $ and ${offset,width,base} in template with value
+func expand(template string, value int) (string, error) {
+	if !strings.Contains(template, "$") {
+		return template, nil
+	}
+
+	text := new(strings.Builder)
+
+	for i := 0; i < len(template); i++ {
+		c := template[i]
+
+		switch {
+		case c == '\\' && i+1 < len(template) && template[i+1] == '$':
+			text.WriteByte('$')
+			i++
+
+		case c == '\\' && i+1 < len(template):
+			//other escapes are left for the name and string parsers
+			text.WriteString(template[i : i+2])
+			i++
+
+		case c == '$' && i+1 < len(template) && template[i+1] == '{':
+			end := strings.IndexByte(template[i:], '}')
+			if end < 0 {
+				return "", ErrBadDirective
+			}
+
+			formatted, err := formatModifier(template[i+2:i+end], value)
+			if err != nil {
+				return "", err
+			}
+
+			text.WriteString(formatted)
+			i = i + end
+
+		case c == '$':
+			text.WriteString(strconv.Itoa(value))
+
+		default:
+			text.WriteByte(c)
+		}
+	}
+
+	return text.String(), nil
+}
+
+//writes value as the modifier offset,width,base says. width and base
+//may be left out
+func formatModifier(modifier string, value int) (string, error) {
+	fields := strings.Split(modifier, ",")
+	if len(fields) > 3 {
+		return "", ErrBadDirective
+	}
+
+	offset, err := strconv.Atoi(fields[0])
+	if err != nil {
+		return "", ErrBadDirective
+	}
+
+	width := 0
+	if len(fields) > 1 {
+		if width, err = strconv.Atoi(fields[1]); err != nil || width < 0 || width > maxNameLength {
+			return "", ErrBadDirective
+		}
+	}
+
+	base := "d"
+	if len(fields) > 2 {
+		base = fields[2]
+	}
+
+	value = value + offset
+	if value < 0 {
+		return "", ErrBadDirective
+	}
+
+	var digits string
+	switch base {
+	case "d":
+		digits = strconv.Itoa(value)
+	case "o":
+		digits = strconv.FormatInt(int64(value), 8)
+	case "x":
+		digits = strconv.FormatInt(int64(value), 16)
+	case "X":
+		digits = strings.ToUpper(strconv.FormatInt(int64(value), 16))
+	case "n", "N":
+		return nibbles(value, width, base == "N"), nil
+	default:
+		return "", ErrBadDirective
+	}
+
+	if len(digits) < width {
+		digits = strings.Repeat("0", width-len(digits)) + digits
+	}
+
+	return digits, nil
+}
+
+//writes value as hex digits in reverse order, with a dot after each
+//digit, until value is used up and at least width characters, dots
+//included, are written. This is what BIND does: the dot after the last
+//digit is only written while width is left, so an even width such as
+//${0,4,n} ends the name in a dot and makes it absolute
+func nibbles(value int, width int, upper bool) string {
+	hex := "0123456789abcdef"
+	if upper {
+		hex = "0123456789ABCDEF"
+	}
+
+	text := new(strings.Builder)
+
+	for {
+		text.WriteByte(hex[value&0xF])
+		value = value >> 4
+		if width > 0 {
+			width--
+		}
+
+		if value == 0 && width == 0 {
+			break
+		}
+
+		text.WriteByte('.')
+		if width > 0 {
+			width--
+		}
+
+		if value == 0 && width == 0 {
+			break
+		}
+	}
+
+	return text.String()
+}
+
+//returns the text of every token
+func tokenTexts(tokens []token) []string {
+	texts := make([]string, len(tokens))
+	for i, t := range tokens {
+		texts[i] = t.text
+	}
+
+	return texts
+}
@@ -0,0 +1,33 @@
+package dnsPacket
+
+import (
+	"testing"
+)
+
+func TestNibbles(t *testing.T) {
+	tables := []struct {
+		value int
+		width int
+		upper bool
+		out   string
+	}{
+		{0, 0, false, "0"},
+		{0, 1, false, "0"},
+		//even widths end in a dot, as in BIND
+		{0, 2, false, "0."},
+		{0, 4, false, "0.0."},
+		{0, 5, false, "0.0.0"},
+		{1, 7, false, "1.0.0.0"},
+		{0xab, 8, false, "b.a.0.0."},
+		{0xab, 8, true, "B.A.0.0."},
+		//wider than width
+		{0x1234, 3, false, "4.3.2.1"},
+		{0x1234, 0, false, "4.3.2.1"},
+	}
+
+	for _, table := range tables {
+		if out := nibbles(table.value, table.width, table.upper); out != table.out {
+			t.Errorf("Fail %#x %d\nGot: %s\nWant: %s", table.value, table.width, out, table.out)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package dnsPacket
+
+import (
+	"bytes"
+	"fmt"
+)
+
+/*
+0  1  2  3  4  5  6  7  8  9  A  B  C  D  E  F
++--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+|QR|   Opcode  |AA|TC|RD|RA| Z|AD|CD|   RCODE   |
++--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+--+
+*/
+
+//Header holds the fixed fields of a DNS packet. The section counts are
+//kept on DNSPacket since they follow from the sections.
+//Rcode only holds the 4 bits found in the header, see
+//DNSPacket.ExtendedRcode for the full EDNS response code
+type Header struct {
+	ID     uint16
+	QR     bool //response
+	Opcode Opcode
+	AA     bool //authoritative answer
+	TC     bool //truncated
+	RD     bool //recursion desired
+	RA     bool //recursion available
+	Z      bool //reserved, must be zero
+	AD     bool //authentic data (RFC 4035)
+	CD     bool //checking disabled (RFC 4035)
+	Rcode  Rcode
+}
+
+//Opcode is the kind of query in a packet
+type Opcode uint8
+
+//Rcode is a response code. Codes above 15 need EDNS
+type Rcode uint16
+
+var opcodeNames = map[Opcode]string{
+	OpcodeStandardQuery: "QUERY",
+	OpcodeInverseQuery:  "IQUERY",
+	OpcodeServerStatus:  "STATUS",
+	OpcodeNotify:        "NOTIFY",
+	OpcodeUpdate:        "UPDATE",
+	OpcodeDSO:           "DSO",
+}
+
+var rcodeNames = map[Rcode]string{
+	RcodeNoError:        "NOERROR",
+	RcodeFormatError:    "FORMERR",
+	RcodeServerFailure:  "SERVFAIL",
+	RcodeNameError:      "NXDOMAIN",
+	RcodeNotImplemented: "NOTIMP",
+	RcodeRefused:        "REFUSED",
+	RcodeYXDomain:       "YXDOMAIN",
+	RcodeYXRRSet:        "YXRRSET",
+	RcodeNXRRSet:        "NXRRSET",
+	RcodeNotAuth:        "NOTAUTH",
+	RcodeNotZone:        "NOTZONE",
+	RcodeDSOTypeNI:      "DSOTYPENI",
+	RcodeBadVersion:     "BADVERS",
+	RcodeBadKey:         "BADKEY",
+	RcodeBadTime:        "BADTIME",
+	RcodeBadMode:        "BADMODE",
+	RcodeBadName:        "BADNAME",
+	RcodeBadAlg:         "BADALG",
+	RcodeBadTrunc:       "BADTRUNC",
+	RcodeBadCookie:      "BADCOOKIE",
+}
+
+func (opcode Opcode) String() string {
+	if name, ok := opcodeNames[opcode]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("OPCODE%d", uint8(opcode))
+}
+
+func (rcode Rcode) String() string {
+	if name, ok := rcodeNames[rcode]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("RCODE%d", uint16(rcode))
+}
+
+//returns the second 16 bit word of the header
+func (h Header) flags() uint16 {
+	flags := uint16(h.Opcode&0xF)<<11 | uint16(h.Rcode&RcodeMask)
+
+	bits := []struct {
+		set  bool
+		mask uint16
+	}{
+		{h.QR, DNSResponse},
+		{h.AA, FlagsAuthoritativeAnswer},
+		{h.TC, FlagsTruncation},
+		{h.RD, FlagsRecurionDesired},
+		{h.RA, FlagsRecursionAvailable},
+		{h.Z, FlagsZ},
+		{h.AD, FlagsAuthenticData},
+		{h.CD, FlagsCheckingDisabled},
+	}
+
+	for _, bit := range bits {
+		if bit.set {
+			flags = flags | bit.mask
+		}
+	}
+
+	return flags
+}
+
+//the inverse of Header.flags
+func decodeHeader(id uint16, flags uint16) Header {
+	return Header{
+		ID:     id,
+		QR:     flags&DNSResponse != 0,
+		Opcode: Opcode(flags >> 11 & 0xF),
+		AA:     flags&FlagsAuthoritativeAnswer != 0,
+		TC:     flags&FlagsTruncation != 0,
+		RD:     flags&FlagsRecurionDesired != 0,
+		RA:     flags&FlagsRecursionAvailable != 0,
+		Z:      flags&FlagsZ != 0,
+		AD:     flags&FlagsAuthenticData != 0,
+		CD:     flags&FlagsCheckingDisabled != 0,
+		Rcode:  Rcode(flags & RcodeMask),
+	}
+}
+
+//String writes the header the way dig does. The status is the 4 bit
+//header rcode, see DNSPacket.String for the extended one
+func (h Header) String() string {
+	return fmt.Sprintf(";; ->>HEADER<<- opcode: %s, status: %s, id: %d\n;; flags:%s;", h.Opcode, h.Rcode, h.ID, h.flagNames())
+}
+
+//the names of the flags that are set, each preceded by a space
+func (h Header) flagNames() string {
+	buf := new(bytes.Buffer)
+
+	names := []struct {
+		set  bool
+		name string
+	}{
+		{h.QR, "qr"}, {h.AA, "aa"}, {h.TC, "tc"}, {h.RD, "rd"},
+		{h.RA, "ra"}, {h.Z, "z"}, {h.AD, "ad"}, {h.CD, "cd"},
+	}
+
+	for _, flag := range names {
+		if flag.set {
+			buf.WriteString(" " + flag.name)
+		}
+	}
+
+	return buf.String()
+}
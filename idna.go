@@ -0,0 +1,780 @@
+package dnsPacket
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//Internationalized domain names (RFC 5890, UTS #46)
+//
+//A label holding characters outside ASCII (a U-label) is written on the
+//wire as an A-label: "xn--" followed by the Punycode (RFC 3492) form of
+//the label.
+//
+//  bücher.example  <->  xn--bcher-kva.example
+//
+//ToASCII processes names the way UTS #46 does for lookups (nontransitional,
+//with the STD3 rules):
+//
+//  1. every code point is mapped with the UTS #46 table, which lower cases
+//     letters, folds full width forms and drops ignored code points
+//  2. the labels are normalized to NFC
+//  3. every code point must be PVALID in IDNA2008 or meet its CONTEXTJ or
+//     CONTEXTO rule (RFC 5892), with the hyphen rules of RFC 5891
+//  4. a name holding a right to left label must follow the Bidi rule
+//     (RFC 5893) in every label
+//
+//ToUnicode checks A-labels the same way. The tables are in idnaTables.go,
+//generated by idnaTablesGen.go.
+//Labels written in ASCII only are left as they are, since DNS names hold
+//labels such as _tcp that are not host names, but A-labels are checked
+
+//the prefix of A-labels
+const acePrefix = "xn--"
+
+//Punycode parameters (RFC 3492 5)
+const (
+	punycodeBase        = 36
+	punycodeTmin        = 1
+	punycodeTmax        = 26
+	punycodeSkew        = 38
+	punycodeDamp        = 700
+	punycodeInitialBias = 72
+	punycodeInitialN    = 128
+	punycodeMaxValue    = 1<<31 - 1
+)
+
+//code points RFC 5892 gives context rules for
+const (
+	zeroWidthNonJoiner = 0x200C
+	zeroWidthJoiner    = 0x200D
+	middleDot          = 0x00B7
+	greekKeraia        = 0x0375
+	hebrewGeresh       = 0x05F3
+	hebrewGershayim    = 0x05F4
+	katakanaMiddleDot  = 0x30FB
+	viramaClass        = 9 //the canonical combining class of viramas
+)
+
+//the IDNA2008 class of a code point (RFC 5892 2)
+type idnaClass uint8
+
+const (
+	idnaDISALLOWED idnaClass = iota
+	idnaPVALID
+	idnaCONTEXTJ
+	idnaCONTEXTO
+)
+
+//the bidi classes the Bidi rule tells apart (RFC 5893 2)
+type bidiClass uint8
+
+const (
+	bidiL bidiClass = iota
+	bidiR
+	bidiAL
+	bidiEN
+	bidiES
+	bidiCS
+	bidiET
+	bidiON
+	bidiBN
+	bidiNSM
+	bidiAN
+	bidiOther
+)
+
+//code points mapped to the same string
+type idnaMapping struct {
+	lo, hi  rune
+	mapping string
+}
+
+//code points of the same IDNA2008 class
+type idnaClassRange struct {
+	lo, hi rune
+	class  idnaClass
+}
+
+//code points of the same bidi class
+type bidiClassRange struct {
+	lo, hi rune
+	class  bidiClass
+}
+
+//code points of the same joining type: C, D, L, R or T
+type joiningTypeRange struct {
+	lo, hi      rune
+	joiningType rune
+}
+
+//ToASCII converts the Unicode labels of a name in presentation format to
+//A-labels so the name can be encoded, mapping and checking them as UTS #46
+//and IDNA2008 say. ASCII labels are left as they are, A-labels already in
+//the name are checked. Errors wrap ErrBadIDN or one of the name errors
+func ToASCII(name string) (string, error) {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '\u3002', '\uFF0E', '\uFF61':
+			return '.'
+		}
+
+		return r
+	}, name)
+
+	//only the length of the A-labels counts
+	labels, err := unescapeLabels(name)
+	if err != nil {
+		return "", fmt.Errorf("dnsPacket: name %q: %w", name, err)
+	}
+
+	unicodeLabels := make([]string, len(labels))
+	for i, label := range labels {
+		if labels[i], unicodeLabels[i], err = toASCIILabel(label); err != nil {
+			return "", fmt.Errorf("dnsPacket: name %q: %w", name, err)
+		}
+	}
+
+	if err := checkBidi(unicodeLabels); err != nil {
+		return "", fmt.Errorf("dnsPacket: name %q: %w", name, err)
+	}
+
+	//the A-labels may be too long, or shorter than the UTF-8 they came from
+	ascii := joinLabels(labels)
+	if _, err := splitLabels(ascii); err != nil {
+		return "", fmt.Errorf("dnsPacket: name %q: %w", name, err)
+	}
+
+	if isAbsolute(name) {
+		return fqdn(ascii), nil
+	}
+
+	return ascii, nil
+}
+
+//ToUnicode converts the A-labels of a name in presentation format to
+//Unicode, to show the name to people. Other labels are left as they are.
+//Errors wrap ErrBadIDN or one of the name errors
+func ToUnicode(name string) (string, error) {
+	labels, err := splitLabels(name)
+	if err != nil {
+		return "", fmt.Errorf("dnsPacket: name %q: %w", name, err)
+	}
+
+	unicodeLabels := make([]string, len(labels))
+	escaped := make([]string, len(labels))
+	for i, label := range labels {
+		if !hasACEPrefix(label) {
+			unicodeLabels[i], escaped[i] = label, escapeLabel(label)
+			continue
+		}
+
+		if unicodeLabels[i], err = toUnicodeLabel(label); err != nil {
+			return "", fmt.Errorf("dnsPacket: name %q: %w", name, err)
+		}
+
+		escaped[i] = escapeUnicodeLabel(unicodeLabels[i])
+	}
+
+	if err := checkBidi(unicodeLabels); err != nil {
+		return "", fmt.Errorf("dnsPacket: name %q: %w", name, err)
+	}
+
+	if isAbsolute(name) {
+		return fqdn(strings.Join(escaped, ".")), nil
+	}
+
+	return strings.Join(escaped, "."), nil
+}
+
+//returns name with its A-labels in Unicode, or as it is if they are not
+//valid A-labels
+func unicodeName(name string) string {
+	if unicodeName, err := ToUnicode(name); err == nil {
+		return unicodeName
+	}
+
+	return name
+}
+
+//returns a copy of rdata with the names it holds in Unicode
+func unicodeRData(rdata PacketProcessor) PacketProcessor {
+	switch record := rdata.(type) {
+	case *RecordTypeCNAME:
+		c := *record
+		c.Target = unicodeName(c.Target)
+		return &c
+	case *RecordTypeDNAME:
+		c := *record
+		c.Target = unicodeName(c.Target)
+		return &c
+	case *RecordTypePTR:
+		c := *record
+		c.Target = unicodeName(c.Target)
+		return &c
+	case *RecordTypeNS:
+		c := *record
+		c.Host = unicodeName(c.Host)
+		return &c
+	case *RecordTypeMX:
+		c := *record
+		c.Exchange = unicodeName(c.Exchange)
+		return &c
+	case *RecordTypeSRV:
+		c := *record
+		c.Target = unicodeName(c.Target)
+		return &c
+	case *RecordTypeSOA:
+		c := *record
+		c.Mname, c.Rname = unicodeName(c.Mname), unicodeName(c.Rname)
+		return &c
+	}
+
+	return rdata
+}
+
+//returns the A-label for a raw label together with its Unicode form
+func toASCIILabel(label string) (string, string, error) {
+	if !utf8.ValidString(label) {
+		return "", "", ErrBadIDN
+	}
+
+	if isASCII(label) && !hasACEPrefix(label) {
+		return label, label, nil
+	}
+
+	if !isASCII(label) {
+		label = nfc(mapLabel(label))
+		if label == "" {
+			return "", "", ErrEmptyLabel
+		}
+	}
+
+	//typed, or mapped to, for example from full width letters
+	if hasACEPrefix(label) {
+		unicodeLabel, err := toUnicodeLabel(label)
+		if err != nil {
+			return "", "", err
+		}
+
+		return label, unicodeLabel, nil
+	}
+
+	if err := checkLabel(label); err != nil {
+		return "", "", err
+	}
+
+	if isASCII(label) {
+		return label, label, nil
+	}
+
+	encoded, err := punycodeEncode(label)
+	if err != nil {
+		return "", "", err
+	}
+
+	return acePrefix + encoded, label, nil
+}
+
+//returns the U-label for an A-label. The A-label must be the one the
+//U-label converts back to, so every name has a single A-label
+func toUnicodeLabel(label string) (string, error) {
+	//A-labels are ASCII, compared without case like any other label
+	label = strings.ToLower(label)
+
+	decoded, err := punycodeDecode(label[len(acePrefix):])
+	if err != nil {
+		return "", err
+	}
+
+	if decoded == "" || isASCII(decoded) || checkLabel(decoded) != nil {
+		return "", ErrBadIDN
+	}
+
+	encoded, err := punycodeEncode(decoded)
+	if err != nil || acePrefix+encoded != label {
+		return "", ErrBadIDN
+	}
+
+	return decoded, nil
+}
+
+//maps the code points of a label with the UTS #46 table
+func mapLabel(label string) string {
+	mapped := new(strings.Builder)
+
+	for _, r := range label {
+		i := sort.Search(len(idnaMappings), func(i int) bool { return idnaMappings[i].hi >= r })
+		if i < len(idnaMappings) && idnaMappings[i].lo <= r {
+			mapped.WriteString(idnaMappings[i].mapping)
+			continue
+		}
+
+		mapped.WriteRune(r)
+	}
+
+	return mapped.String()
+}
+
+//checks the rules a U-label must follow (RFC 5891 5.4, RFC 5892, UTS #46 4.1)
+func checkLabel(label string) error {
+	if nfc(label) != label {
+		return ErrBadIDN
+	}
+
+	if strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+		return ErrBadIDN
+	}
+
+	runes := []rune(label)
+
+	//"ab--" is kept for A-labels and future prefixes
+	if len(runes) >= 4 && runes[2] == '-' && runes[3] == '-' {
+		return ErrBadIDN
+	}
+
+	if unicode.Is(unicode.M, runes[0]) {
+		return ErrBadIDN
+	}
+
+	for i, r := range runes {
+		switch classOf(r) {
+		case idnaPVALID:
+		case idnaCONTEXTJ:
+			if !contextJ(runes, i) {
+				return ErrBadIDN
+			}
+		case idnaCONTEXTO:
+			if !contextO(runes, i) {
+				return ErrBadIDN
+			}
+		default:
+			return ErrBadIDN
+		}
+	}
+
+	return nil
+}
+
+//the CONTEXTJ rules for the zero width joiners (RFC 5892 A.1, A.2)
+func contextJ(runes []rune, i int) bool {
+	if i > 0 && combiningClass(runes[i-1]) == viramaClass {
+		return true
+	}
+
+	if runes[i] != zeroWidthNonJoiner {
+		return false
+	}
+
+	//(L|D) T* ZWNJ T* (R|D)
+	before := i - 1
+	for before >= 0 && joiningTypeOf(runes[before]) == 'T' {
+		before--
+	}
+
+	after := i + 1
+	for after < len(runes) && joiningTypeOf(runes[after]) == 'T' {
+		after++
+	}
+
+	if before < 0 || after >= len(runes) {
+		return false
+	}
+
+	left, right := joiningTypeOf(runes[before]), joiningTypeOf(runes[after])
+
+	return (left == 'L' || left == 'D') && (right == 'R' || right == 'D')
+}
+
+//the CONTEXTO rules (RFC 5892 A.3 to A.9)
+func contextO(runes []rune, i int) bool {
+	r := runes[i]
+
+	switch {
+	case r == middleDot:
+		return i > 0 && i+1 < len(runes) && runes[i-1] == 'l' && runes[i+1] == 'l'
+
+	case r == greekKeraia:
+		return i+1 < len(runes) && unicode.Is(unicode.Greek, runes[i+1])
+
+	case r == hebrewGeresh || r == hebrewGershayim:
+		return i > 0 && unicode.Is(unicode.Hebrew, runes[i-1])
+
+	case r == katakanaMiddleDot:
+		for _, c := range runes {
+			if c != katakanaMiddleDot && unicode.In(c, unicode.Hiragana, unicode.Katakana, unicode.Han) {
+				return true
+			}
+		}
+
+		return false
+
+	//the two sets of Arabic-Indic digits may not be mixed
+	case r >= 0x0660 && r <= 0x0669:
+		return !containsRange(runes, 0x06F0, 0x06F9)
+
+	case r >= 0x06F0 && r <= 0x06F9:
+		return !containsRange(runes, 0x0660, 0x0669)
+	}
+
+	return false
+}
+
+//checks the Bidi rule (RFC 5893 2) when a label is written right to left.
+//ASCII labels that are not host names are left out, as in ToASCII
+func checkBidi(labels []string) error {
+	rightToLeft := false
+	for _, label := range labels {
+		for _, r := range label {
+			if class := bidiClassOf(r); class == bidiR || class == bidiAL || class == bidiAN {
+				rightToLeft = true
+			}
+		}
+	}
+
+	if !rightToLeft {
+		return nil
+	}
+
+	for _, label := range labels {
+		if isASCII(label) && !isHostLabel(label) {
+			continue
+		}
+
+		if !bidiRule(label) {
+			return ErrBadIDN
+		}
+	}
+
+	return nil
+}
+
+//reports whether a label follows the six conditions of the Bidi rule
+func bidiRule(label string) bool {
+	runes := []rune(label)
+	if len(runes) == 0 {
+		return true
+	}
+
+	//the end is the last code point that is not NSM
+	end := len(runes) - 1
+	for end > 0 && bidiClassOf(runes[end]) == bidiNSM {
+		end--
+	}
+
+	last := bidiClassOf(runes[end])
+
+	switch bidiClassOf(runes[0]) {
+	case bidiR, bidiAL:
+		hasEN, hasAN := false, false
+
+		for _, r := range runes {
+			switch bidiClassOf(r) {
+			case bidiR, bidiAL, bidiES, bidiCS, bidiET, bidiON, bidiBN, bidiNSM:
+			case bidiEN:
+				hasEN = true
+			case bidiAN:
+				hasAN = true
+			default:
+				return false
+			}
+		}
+
+		return !(hasEN && hasAN) && (last == bidiR || last == bidiAL || last == bidiEN || last == bidiAN)
+
+	case bidiL:
+		for _, r := range runes {
+			switch bidiClassOf(r) {
+			case bidiL, bidiEN, bidiES, bidiCS, bidiET, bidiON, bidiBN, bidiNSM:
+			default:
+				return false
+			}
+		}
+
+		return last == bidiL || last == bidiEN
+	}
+
+	return false
+}
+
+func classOf(r rune) idnaClass {
+	i := sort.Search(len(idnaClasses), func(i int) bool { return idnaClasses[i].hi >= r })
+	if i < len(idnaClasses) && idnaClasses[i].lo <= r {
+		return idnaClasses[i].class
+	}
+
+	return idnaDISALLOWED
+}
+
+func bidiClassOf(r rune) bidiClass {
+	i := sort.Search(len(bidiClasses), func(i int) bool { return bidiClasses[i].hi >= r })
+	if i < len(bidiClasses) && bidiClasses[i].lo <= r {
+		return bidiClasses[i].class
+	}
+
+	return bidiL
+}
+
+//returns the joining type of r, U when it has none
+func joiningTypeOf(r rune) rune {
+	i := sort.Search(len(joiningTypes), func(i int) bool { return joiningTypes[i].hi >= r })
+	if i < len(joiningTypes) && joiningTypes[i].lo <= r {
+		return joiningTypes[i].joiningType
+	}
+
+	return 'U'
+}
+
+func containsRange(runes []rune, lo rune, hi rune) bool {
+	for _, r := range runes {
+		if r >= lo && r <= hi {
+			return true
+		}
+	}
+
+	return false
+}
+
+//reports whether a label only holds letters, digits and hyphens
+func isHostLabel(label string) bool {
+	for i := 0; i < len(label); i++ {
+		c := label[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-') {
+			return false
+		}
+	}
+
+	return true
+}
+
+//writes a U-label escaped like escapeLabel, but with the characters
+//outside ASCII left as they are
+func escapeUnicodeLabel(label string) string {
+	escaped := new(strings.Builder)
+
+	for _, r := range label {
+		if r < utf8.RuneSelf {
+			escaped.WriteString(escapeLabel(string(r)))
+			continue
+		}
+
+		escaped.WriteRune(r)
+	}
+
+	return escaped.String()
+}
+
+func hasACEPrefix(label string) bool {
+	return len(label) >= len(acePrefix) && strings.EqualFold(label[:len(acePrefix)], acePrefix)
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
+
+//reports whether a name in presentation format ends in a dot
+func isAbsolute(name string) bool {
+	return name != "" && fqdn(name) == name
+}
+
+//encodes a label with Punycode (RFC 3492 6.3), without the prefix
+func punycodeEncode(label string) (string, error) {
+	input := []rune(label)
+	output := make([]byte, 0, len(label))
+
+	for _, r := range input {
+		if r < utf8.RuneSelf {
+			output = append(output, byte(r))
+		}
+	}
+
+	basic := len(output)
+	handled := basic
+	if basic > 0 {
+		output = append(output, '-')
+	}
+
+	n, delta, bias := punycodeInitialN, 0, punycodeInitialBias
+
+	for handled < len(input) {
+		//the smallest code point not handled yet
+		m := punycodeMaxValue
+		for _, r := range input {
+			if int(r) >= n && int(r) < m {
+				m = int(r)
+			}
+		}
+
+		if (m - n) > (punycodeMaxValue-delta)/(handled+1) {
+			return "", ErrBadIDN
+		}
+
+		delta = delta + (m-n)*(handled+1)
+		n = m
+
+		for _, r := range input {
+			if int(r) < n {
+				delta++
+				if delta > punycodeMaxValue {
+					return "", ErrBadIDN
+				}
+			}
+
+			if int(r) != n {
+				continue
+			}
+
+			q := delta
+			for k := punycodeBase; ; k = k + punycodeBase {
+				t := punycodeThreshold(k, bias)
+				if q < t {
+					break
+				}
+
+				output = append(output, punycodeDigit(t+(q-t)%(punycodeBase-t)))
+				q = (q - t) / (punycodeBase - t)
+			}
+
+			output = append(output, punycodeDigit(q))
+			bias = punycodeAdapt(delta, handled+1, handled == basic)
+			delta = 0
+			handled++
+		}
+
+		delta++
+		n++
+	}
+
+	return string(output), nil
+}
+
+//decodes a label written with Punycode (RFC 3492 6.2), without the prefix
+func punycodeDecode(encoded string) (string, error) {
+	output := make([]rune, 0, len(encoded))
+	pos := 0
+
+	//the basic code points come before the last delimiter
+	if last := strings.LastIndexByte(encoded, '-'); last >= 0 {
+		for _, c := range []byte(encoded[:last]) {
+			if c >= utf8.RuneSelf {
+				return "", ErrBadIDN
+			}
+
+			output = append(output, rune(c))
+		}
+
+		pos = last + 1
+	}
+
+	n, i, bias := punycodeInitialN, 0, punycodeInitialBias
+
+	for pos < len(encoded) {
+		oldI, w := i, 1
+
+		for k := punycodeBase; ; k = k + punycodeBase {
+			if pos >= len(encoded) {
+				return "", ErrBadIDN
+			}
+
+			digit, ok := punycodeValue(encoded[pos])
+			pos++
+			if !ok || digit > (punycodeMaxValue-i)/w {
+				return "", ErrBadIDN
+			}
+
+			i = i + digit*w
+
+			t := punycodeThreshold(k, bias)
+			if digit < t {
+				break
+			}
+
+			if w > punycodeMaxValue/(punycodeBase-t) {
+				return "", ErrBadIDN
+			}
+
+			w = w * (punycodeBase - t)
+		}
+
+		bias = punycodeAdapt(i-oldI, len(output)+1, oldI == 0)
+
+		if i/(len(output)+1) > punycodeMaxValue-n {
+			return "", ErrBadIDN
+		}
+
+		n = n + i/(len(output)+1)
+		i = i % (len(output) + 1)
+
+		if n > unicode.MaxRune || (n >= 0xD800 && n <= 0xDFFF) {
+			return "", ErrBadIDN
+		}
+
+		output = append(output, 0)
+		copy(output[i+1:], output[i:])
+		output[i] = rune(n)
+		i++
+	}
+
+	return string(output), nil
+}
+
+func punycodeThreshold(k int, bias int) int {
+	switch {
+	case k <= bias+punycodeTmin:
+		return punycodeTmin
+	case k >= bias+punycodeTmax:
+		return punycodeTmax
+	}
+
+	return k - bias
+}
+
+//RFC 3492 6.1
+func punycodeAdapt(delta int, points int, first bool) int {
+	if first {
+		delta = delta / punycodeDamp
+	} else {
+		delta = delta / 2
+	}
+
+	delta = delta + delta/points
+
+	k := 0
+	for delta > ((punycodeBase-punycodeTmin)*punycodeTmax)/2 {
+		delta = delta / (punycodeBase - punycodeTmin)
+		k = k + punycodeBase
+	}
+
+	return k + (punycodeBase-punycodeTmin+1)*delta/(delta+punycodeSkew)
+}
+
+//a-z are 0 to 25, 0-9 are 26 to 35
+func punycodeDigit(d int) byte {
+	if d < 26 {
+		return byte('a' + d)
+	}
+
+	return byte('0' + d - 26)
+}
+
+func punycodeValue(c byte) (int, bool) {
+	switch {
+	case c >= 'a' && c <= 'z':
+		return int(c - 'a'), true
+	case c >= 'A' && c <= 'Z':
+		return int(c - 'A'), true
+	case c >= '0' && c <= '9':
+		return int(c-'0') + 26, true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,314 @@
+//go:build ignore
+
+//Generates idnaTables.go, the Unicode data used by ToASCII and ToUnicode,
+//from the files the Unicode Consortium and IANA publish for a version:
+//
+//  https://www.unicode.org/Public/idna/<version>/IdnaMappingTable.txt
+//  https://www.unicode.org/Public/<version>/ucd/UnicodeData.txt
+//  https://www.unicode.org/Public/<version>/ucd/CompositionExclusions.txt
+//  https://www.unicode.org/Public/<version>/ucd/extracted/DerivedJoiningType.txt
+//  https://www.iana.org/assignments/idna-tables-<version>/idna-tables-properties.csv
+//
+//Put them in one directory and run
+//
+//  go run idnaTablesGen.go -data <directory> -version <version>
+
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//a run of code points sharing a value
+type runeRange struct {
+	lo, hi rune
+	value  string
+}
+
+var dataDir = flag.String("data", ".", "directory holding the data files")
+var version = flag.String("version", "", "Unicode version of the data files")
+
+func main() {
+	flag.Parse()
+
+	if *version == "" {
+		log.Fatal("-version is required")
+	}
+
+	out := new(strings.Builder)
+
+	fmt.Fprintf(out, "// Code generated by go run idnaTablesGen.go -version %s; DO NOT EDIT.\n\n", *version)
+	fmt.Fprintf(out, "package dnsPacket\n\n")
+
+	mappings, classes := idnaMappings(), idnaClasses()
+	combining, bidi, decompositions := unicodeData()
+	compositions := primaryComposites(decompositions, combining)
+
+	writeRanges(out, "UTS #46 mappings, ignored code points map to \"\"", "idnaMappings", "idnaMapping", mappings, strconv.QuoteToASCII)
+	writeRanges(out, "the IDNA2008 code points that may be used (RFC 5892)", "idnaClasses", "idnaClassRange", classes, idnaClassName)
+	writeRanges(out, "the code points with a canonical combining class other than 0", "combiningClasses", "combiningClassRange", combining, identity)
+	writeRanges(out, "the bidi class of the code points not in class L", "bidiClasses", "bidiClassRange", bidi, bidiClassName)
+	writeRanges(out, "the joining type of the code points not of type U", "joiningTypes", "joiningTypeRange", joiningTypes(), quoteByte)
+
+	fmt.Fprintf(out, "//canonical decompositions, one level deep\nvar decompositions = map[rune]string{\n")
+	for _, r := range sortedKeys(decompositions) {
+		fmt.Fprintf(out, "\t0x%05x: %s,\n", r, strconv.QuoteToASCII(decompositions[r]))
+	}
+	fmt.Fprintf(out, "}\n\n")
+
+	fmt.Fprintf(out, "//the primary composites of canonical composition, by the pair they\n//compose from\nvar compositions = map[[2]rune]rune{\n")
+	for _, r := range sortedKeys(compositions) {
+		pair := []rune(compositions[r])
+		fmt.Fprintf(out, "\t{0x%05x, 0x%05x}: %#x,\n", pair[0], pair[1], r)
+	}
+	fmt.Fprintf(out, "}\n")
+
+	if err := os.WriteFile("idnaTables.go", []byte(out.String()), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+//reads the mapped and ignored code points of IdnaMappingTable.txt
+func idnaMappings() []runeRange {
+	ranges := make([]runeRange, 0)
+
+	readFields("IdnaMappingTable.txt", ";", func(fields []string) {
+		lo, hi := parseRange(fields[0], "..")
+
+		switch fields[1] {
+		case "mapped":
+			ranges = append(ranges, runeRange{lo, hi, parseRunes(fields[2])})
+		case "ignored":
+			ranges = append(ranges, runeRange{lo, hi, ""})
+		}
+	})
+
+	return ranges
+}
+
+//reads the PVALID, CONTEXTJ and CONTEXTO code points of the IANA table
+func idnaClasses() []runeRange {
+	ranges := make([]runeRange, 0)
+
+	readFields("idna-tables-properties.csv", ",", func(fields []string) {
+		switch fields[1] {
+		case "PVALID", "CONTEXTJ", "CONTEXTO":
+		default:
+			return
+		}
+
+		lo, hi := parseRange(fields[0], "-")
+		ranges = appendRange(ranges, runeRange{lo, hi, fields[1]})
+	})
+
+	return ranges
+}
+
+//reads the combining classes, bidi classes and canonical decompositions
+//of UnicodeData.txt
+func unicodeData() (combining []runeRange, bidi []runeRange, decompositions map[rune]string) {
+	decompositions = make(map[rune]string)
+	first := rune(-1)
+
+	readFields("UnicodeData.txt", ";", func(fields []string) {
+		lo, _ := parseRange(fields[0], "..")
+		hi := lo
+
+		//ranges are given as a First and a Last line
+		switch {
+		case strings.HasSuffix(fields[1], ", First>"):
+			first = lo
+			return
+		case strings.HasSuffix(fields[1], ", Last>"):
+			lo = first
+		}
+
+		if fields[3] != "0" {
+			combining = appendRange(combining, runeRange{lo, hi, fields[3]})
+		}
+
+		if fields[4] != "L" {
+			bidi = appendRange(bidi, runeRange{lo, hi, fields[4]})
+		}
+
+		if fields[5] != "" && !strings.HasPrefix(fields[5], "<") {
+			decompositions[lo] = parseRunes(fields[5])
+		}
+	})
+
+	return combining, bidi, decompositions
+}
+
+//returns the characters canonical composition produces (UAX #15):
+//canonical decompositions into two characters, leaving out the
+//composition exclusions and the decompositions starting with a non-starter
+func primaryComposites(decompositions map[rune]string, combining []runeRange) map[rune]string {
+	excluded := make(map[rune]bool)
+
+	readFields("CompositionExclusions.txt", ";", func(fields []string) {
+		lo, hi := parseRange(fields[0], "..")
+		for r := lo; r <= hi; r++ {
+			excluded[r] = true
+		}
+	})
+
+	starter := func(r rune) bool {
+		for _, c := range combining {
+			if r >= c.lo && r <= c.hi {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	compositions := make(map[rune]string)
+	for r, decomposition := range decompositions {
+		pair := []rune(decomposition)
+		if len(pair) == 2 && !excluded[r] && starter(r) && starter(pair[0]) {
+			compositions[r] = decomposition
+		}
+	}
+
+	return compositions
+}
+
+//reads DerivedJoiningType.txt
+func joiningTypes() []runeRange {
+	ranges := make([]runeRange, 0)
+
+	readFields("DerivedJoiningType.txt", ";", func(fields []string) {
+		lo, hi := parseRange(fields[0], "..")
+		ranges = append(ranges, runeRange{lo, hi, fields[1]})
+	})
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].lo < ranges[j].lo })
+
+	merged := make([]runeRange, 0, len(ranges))
+	for _, r := range ranges {
+		merged = appendRange(merged, r)
+	}
+
+	return merged
+}
+
+//calls f with the trimmed fields of every line of the file that is not
+//a comment or a header
+func readFields(name string, separator string, f func(fields []string)) {
+	file, err := os.Open(filepath.Join(*dataDir, name))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "Codepoint,") {
+			continue
+		}
+
+		fields := strings.Split(line, separator)
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+
+		f(fields)
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+//parses "0041" or "0041..005A"
+func parseRange(field string, separator string) (rune, rune) {
+	loText, hiText, isRange := strings.Cut(field, separator)
+
+	lo, err := strconv.ParseUint(loText, 16, 32)
+	if err != nil {
+		log.Fatalf("bad code point %q", field)
+	}
+
+	if !isRange {
+		return rune(lo), rune(lo)
+	}
+
+	hi, err := strconv.ParseUint(hiText, 16, 32)
+	if err != nil {
+		log.Fatalf("bad code point %q", field)
+	}
+
+	return rune(lo), rune(hi)
+}
+
+//parses code points written in hex, separated by spaces
+func parseRunes(field string) string {
+	runes := make([]rune, 0)
+	for _, cp := range strings.Fields(field) {
+		r, _ := parseRange(cp, "..")
+		runes = append(runes, r)
+	}
+
+	return string(runes)
+}
+
+//appends r, merging it with the last range if it follows it with the
+//same value
+func appendRange(ranges []runeRange, r runeRange) []runeRange {
+	if n := len(ranges); n > 0 && ranges[n-1].hi+1 == r.lo && ranges[n-1].value == r.value {
+		ranges[n-1].hi = r.hi
+		return ranges
+	}
+
+	return append(ranges, r)
+}
+
+func writeRanges(out *strings.Builder, doc string, name string, typeName string, ranges []runeRange, value func(string) string) {
+	fmt.Fprintf(out, "//%s\nvar %s = []%s{\n", doc, name, typeName)
+	for _, r := range ranges {
+		fmt.Fprintf(out, "\t{%#x, %#x, %s},\n", r.lo, r.hi, value(r.value))
+	}
+	fmt.Fprintf(out, "}\n\n")
+}
+
+func idnaClassName(class string) string {
+	return map[string]string{"PVALID": "idnaPVALID", "CONTEXTJ": "idnaCONTEXTJ", "CONTEXTO": "idnaCONTEXTO"}[class]
+}
+
+//the classes the Bidi rule (RFC 5893) tells apart, the rest are bidiOther
+func bidiClassName(class string) string {
+	switch class {
+	case "R", "AL", "EN", "ES", "CS", "ET", "ON", "BN", "NSM", "AN":
+		return "bidi" + class
+	}
+
+	return "bidiOther"
+}
+
+func identity(s string) string {
+	return s
+}
+
+func quoteByte(s string) string {
+	return strconv.QuoteRune(rune(s[0]))
+}
+
+func sortedKeys(m map[rune]string) []rune {
+	keys := make([]rune, 0, len(m))
+	for r := range m {
+		keys = append(keys, r)
+	}
+
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	return keys
+}
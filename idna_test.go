@@ -0,0 +1,192 @@
+package dnsPacket
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPunycode(t *testing.T) {
+	tables := []struct {
+		in  string
+		out string
+	}{
+		{"bücher", "bcher-kva"},
+		{"münchen", "mnchen-3ya"},
+		{"中国", "fiqs8s"},
+		{"яндекс", "d1acpjx3f"},
+		{"ü-x", "-x-wka"},
+		//RFC 3492 7.1 (L)
+		{"3年B組金八先生", "3B-ww4c5e180e575a65lsy2b"},
+	}
+
+	for _, table := range tables {
+		encoded, err := punycodeEncode(table.in)
+		if err != nil || encoded != table.out {
+			t.Errorf("Fail %q\nGot: %q %v\nWant: %q", table.in, encoded, err, table.out)
+		}
+
+		decoded, err := punycodeDecode(table.out)
+		if err != nil || decoded != table.in {
+			t.Errorf("Fail %q\nGot: %q %v\nWant: %q", table.out, decoded, err, table.in)
+		}
+	}
+}
+
+func TestToASCII(t *testing.T) {
+	tables := []struct {
+		in  string
+		out string
+	}{
+		{"bücher.example.", "xn--bcher-kva.example."},
+		{"Bücher.Example", "xn--bcher-kva.Example"},
+		{"яндекс.рф", "xn--d1acpjx3f.xn--p1ai"},
+		{"中国。example．org｡", "xn--fiqs8s.example.org."},
+		{"xn--bcher-kva.example.", "xn--bcher-kva.example."},
+		{"_sip._tcp.example.", "_sip._tcp.example."},
+		{".", "."},
+		//UTS #46 mapping: full width letters, ß kept, ignored soft hyphen
+		{"ＡＢＣ.example", "abc.example"},
+		{"ßtraße.example.", "xn--trae-unad.example."},
+		{"Ⅻ\u00ADi.example.", "xiii.example."},
+		//NFC: composed and decomposed forms have the same A-label
+		{"bu\u0308cher.example.", "xn--bcher-kva.example."},
+		//CONTEXTJ: after a virama, or between joining letters
+		{"क्\u200Dष.example.", "xn--11b2ezcw70k.example."},
+		{"ب\u200Cب.example.", "xn--ngba799q.example."},
+		//CONTEXTO
+		{"l·l.example.", "xn--ll-0ea.example."},
+		{"א׳.example.", "xn--4db4e.example."},
+		//Bidi
+		{"العربية.example.", "xn--mgbcd4a2b0d2b.example."},
+		{"א1.example.", "xn--1-zhc.example."},
+		{"_tcp.العربية.example.", "_tcp.xn--mgbcd4a2b0d2b.example."},
+		//longer than 63 octets in UTF-8, not as A-labels
+		{strings.Repeat("ü", 40) + ".example.", "xn--td" + strings.Repeat("a", 40) + ".example."},
+		{strings.Repeat("ภาษาไทย", 4) + ".example.", "xn--o3caaa5bbbbnccc4fddd4lbdbdbdb64bgagg.example."},
+	}
+
+	for _, table := range tables {
+		ascii, err := ToASCII(table.in)
+		if err != nil || ascii != table.out {
+			t.Errorf("Fail %q\nGot: %q %v\nWant: %q", table.in, ascii, err, table.out)
+		}
+	}
+}
+
+func TestToASCIIErrors(t *testing.T) {
+	tables := []struct {
+		in  string
+		err error
+	}{
+		{"-bücher.example.", ErrBadIDN},
+		{"bücher-.example.", ErrBadIDN},
+		{"bü--cher.example.", ErrBadIDN},
+		{"\u0301bücher.example.", ErrBadIDN},
+		{"b\\255cher.example.", ErrBadIDN},
+		{"xn--bcher-kva9.example.", ErrBadIDN},
+		{"xn--abc.example.", ErrBadIDN},
+		//the A-labels are too long, not just the UTF-8
+		{strings.Repeat("ü", 58) + ".example.", ErrLabelTooLong},
+		{strings.Repeat(strings.Repeat("ü", 57)+".", 4), ErrNameTooLong},
+		{"a..example.", ErrEmptyLabel},
+		{"\u00AD.example.", ErrEmptyLabel},
+		//not valid in IDNA2008
+		{"☃.example.", ErrBadIDN},
+		{"xn--n3h.example.", ErrBadIDN},
+		//CONTEXTJ
+		{"a\u200Db.example.", ErrBadIDN},
+		{"a\u200Cb.example.", ErrBadIDN},
+		//CONTEXTO
+		{"a·b.example.", ErrBadIDN},
+		{"x׳.example.", ErrBadIDN},
+		{"ا١۱.example.", ErrBadIDN},
+		//Bidi: an RTL label starting with a digit, an LTR label in an RTL
+		//name starting with one, mixed RTL and LTR letters
+		{"1א.example.", ErrBadIDN},
+		{"אב.1a.example.", ErrBadIDN},
+		{"אaב.example.", ErrBadIDN},
+		//an A-label holding an upper case letter
+		{"xn--wca.example.", ErrBadIDN},
+	}
+
+	for _, table := range tables {
+		if _, err := ToASCII(table.in); !errors.Is(err, table.err) {
+			t.Errorf("Fail %q\nGot: %v\nWant: %v", table.in, err, table.err)
+		}
+	}
+}
+
+func TestToUnicode(t *testing.T) {
+	tables := []struct {
+		in  string
+		out string
+	}{
+		{"xn--bcher-kva.example.", "bücher.example."},
+		{"XN--BCHER-KVA.example", "bücher.example"},
+		{"xn--d1acpjx3f.xn--p1ai.", "яндекс.рф."},
+		{`a\.b.xn--fiqs8s.`, `a\.b.中国.`},
+		{"www.example.", "www.example."},
+	}
+
+	for _, table := range tables {
+		unicodeName, err := ToUnicode(table.in)
+		if err != nil || unicodeName != table.out {
+			t.Errorf("Fail %q\nGot: %q %v\nWant: %q", table.in, unicodeName, err, table.out)
+		}
+	}
+
+	for _, name := range []string{"xn--.example.", "xn--n3h.example.", "xn--4dbc.1a.example."} {
+		if _, err := ToUnicode(name); !errors.Is(err, ErrBadIDN) {
+			t.Errorf("Fail %q\nGot: %v\nWant: %v", name, err, ErrBadIDN)
+		}
+	}
+}
+
+func TestNewIDNQuery(t *testing.T) {
+	query, err := NewIDNQuery("bücher.example.", DNSRecordTypeMX)
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
+	if query.Questions[0].Qname != "xn--bcher-kva.example." {
+		t.Errorf("Fail\nGot: %s\nWant: %s", query.Questions[0].Qname, "xn--bcher-kva.example.")
+	}
+
+	reply := query.Reply()
+	reply.AddRR(SectionAnswer, "xn--bcher-kva.example.", 300, &RecordTypeMX{Preference: 10, Exchange: "mail.xn--bcher-kva.example"})
+
+	tables := []struct {
+		got  string
+		want string
+	}{
+		{reply.Questions[0].String(), ";xn--bcher-kva.example.\tIN\tMX"},
+		{reply.Questions[0].UnicodeString(), ";bücher.example.\tIN\tMX"},
+		{reply.Answers[0].String(), "xn--bcher-kva.example.\t300\tIN\tMX\t10 mail.xn--bcher-kva.example."},
+		{reply.Answers[0].UnicodeString(), "bücher.example.\t300\tIN\tMX\t10 mail.bücher.example."},
+	}
+
+	for _, table := range tables {
+		if table.got != table.want {
+			t.Errorf("Fail\nGot: %s\nWant: %s", table.got, table.want)
+		}
+	}
+
+	if !strings.Contains(reply.UnicodeString(), "\n;bücher.example.\tIN\tMX\n") {
+		t.Errorf("Fail\nGot:\n%s", reply.UnicodeString())
+	}
+
+	//the record printed is left alone
+	if reply.Answers[0].RData.(*RecordTypeMX).Exchange != "mail.xn--bcher-kva.example" {
+		t.Errorf("Fail\nGot: %s", reply.Answers[0].RData.(*RecordTypeMX).Exchange)
+	}
+
+	thai, err := NewIDNQuery(strings.Repeat("ภาษาไทย", 4)+".example.", DNSRecordTypeA)
+	if err != nil || thai.Questions[0].Qname != "xn--o3caaa5bbbbnccc4fddd4lbdbdbdb64bgagg.example." {
+		t.Errorf("Fail\nGot: %v %v\nWant: %s", thai, err, "xn--o3caaa5bbbbnccc4fddd4lbdbdbdb64bgagg.example.")
+	}
+
+	if _, err := NewIDNQuery("-bücher.example.", DNSRecordTypeA); !errors.Is(err, ErrBadIDN) {
+		t.Errorf("Fail\nGot: %v\nWant: %v", err, ErrBadIDN)
+	}
+}
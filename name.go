@@ -0,0 +1,178 @@
+package dnsPacket
+
+//Name is an absolute domain name held as its raw labels, so dots and
+//other bytes inside a label are kept apart from the dots between them.
+//Names are checked against the 63 octet label and 255 octet name limits
+//when they are made. The zero Name is the root
+type Name struct {
+	labels []string //leftmost first, without escapes
+}
+
+//NewName parses a name in presentation format. \X and \DDD escape a
+//byte (RFC 1035 5.1) and the trailing dot may be left out, names are
+//always taken as relative to the root
+//
+//  name, err := NewName("www.example.org.")
+func NewName(s string) (Name, error) {
+	labels, err := splitLabels(s)
+	if err != nil {
+		return Name{}, err
+	}
+
+	return Name{labels: labels}, nil
+}
+
+//NameFromLabels makes a name out of raw labels, leftmost first.
+//A label may hold any byte, including dots
+func NameFromLabels(labels []string) (Name, error) {
+	wireLength := 1 //the terminating zero length label
+
+	for _, label := range labels {
+		if len(label) == 0 {
+			return Name{}, ErrEmptyLabel
+		}
+
+		if len(label) > maxLabelLength {
+			return Name{}, ErrLabelTooLong
+		}
+
+		wireLength = wireLength + len(label) + 1
+	}
+
+	if wireLength > maxNameLength {
+		return Name{}, ErrNameTooLong
+	}
+
+	return Name{labels: append([]string(nil), labels...)}, nil
+}
+
+//DecodeName reads the wire form name starting at msg[off:], following
+//compression pointers, and returns it with the number of bytes it takes
+//at off. A malformed name returns a *DecodeError
+func DecodeName(msg []byte, off int) (Name, int, error) {
+	text, n, err := decodeQname(msg, off)
+	if err != nil {
+		return Name{}, 0, &DecodeError{Offset: off, Err: err}
+	}
+
+	//decodeQname escapes the labels so they split back unchanged
+	name, err := NewName(text)
+	if err != nil {
+		return Name{}, 0, &DecodeError{Offset: off, Err: err}
+	}
+
+	return name, n, nil
+}
+
+//Encode appends the wire form of the name to msg, which must hold the
+//message encoded so far. The name is compressed against the names in
+//compression and recorded in it, pass a nil map to write it in full
+func (n Name) Encode(msg []byte, compression map[string]int) []byte {
+	//the labels were checked when the name was made
+	msg, _ = encodeName(msg, n.String(), compression, compression != nil)
+
+	return msg
+}
+
+//String returns the name in presentation format, fully qualified and
+//with the bytes that need it escaped
+func (n Name) String() string {
+	return fqdn(joinLabels(n.labels))
+}
+
+//Labels returns the raw labels of the name, leftmost first. The root
+//has none
+func (n Name) Labels() []string {
+	return append([]string(nil), n.labels...)
+}
+
+//IsRoot reports whether the name is the root
+func (n Name) IsRoot() bool {
+	return len(n.labels) == 0
+}
+
+//Parent returns the name without its leftmost label. The parent of the
+//root is the root
+func (n Name) Parent() Name {
+	if n.IsRoot() {
+		return n
+	}
+
+	return Name{labels: n.labels[1:]}
+}
+
+//Equal reports whether two names are the same, ignoring the case of
+//ASCII letters (RFC 4343)
+func (n Name) Equal(other Name) bool {
+	return n.Compare(other) == 0
+}
+
+//Compare orders names canonically (RFC 4034 6.1): label by label from
+//the root, ignoring the case of ASCII letters, with a name sorting before
+//the names below it. It returns -1, 0 or 1
+func (n Name) Compare(other Name) int {
+	return compareNames(n.labels, other.labels)
+}
+
+//IsSubdomainOf reports whether the name is parent or below it
+func (n Name) IsSubdomainOf(parent Name) bool {
+	if len(n.labels) < len(parent.labels) {
+		return false
+	}
+
+	return compareNames(n.labels[len(n.labels)-len(parent.labels):], parent.labels) == 0
+}
+
+//IsChildOf reports whether the name is exactly one label below parent
+func (n Name) IsChildOf(parent Name) bool {
+	return len(n.labels) == len(parent.labels)+1 && n.IsSubdomainOf(parent)
+}
+
+//compares two names given as raw labels in canonical order
+func compareNames(a []string, b []string) int {
+	for i := 1; i <= len(a) && i <= len(b); i++ {
+		if c := compareLabels(a[len(a)-i], b[len(b)-i]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+
+	return 0
+}
+
+//compares two labels as octet strings with the ASCII letters lower cased
+func compareLabels(a string, b string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		ca, cb := lowerASCII(a[i]), lowerASCII(b[i])
+		if ca != cb {
+			if ca < cb {
+				return -1
+			}
+
+			return 1
+		}
+	}
+
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+
+	return 0
+}
+
+func lowerASCII(c byte) byte {
+	if c >= 'A' && c <= 'Z' {
+		return c + 'a' - 'A'
+	}
+
+	return c
+}
@@ -0,0 +1,199 @@
+package dnsPacket
+
+import (
+	"bytes"
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestNewName(t *testing.T) {
+	tables := []struct {
+		in     string
+		out    string
+		labels []string
+	}{
+		{"www.example.org.", "www.example.org.", []string{"www", "example", "org"}},
+		{"www.example.org", "www.example.org.", []string{"www", "example", "org"}},
+		{".", ".", nil},
+		{"", ".", nil},
+		{`a\.b.example.`, `a\.b.example.`, []string{"a.b", "example"}},
+		{`\065\032b.example`, `A\ b.example.`, []string{"A b", "example"}},
+	}
+
+	for _, table := range tables {
+		name, err := NewName(table.in)
+		if err != nil {
+			t.Errorf("Fail %q\nGot error: %s", table.in, err)
+			continue
+		}
+
+		if name.String() != table.out || strings.Join(name.Labels(), "|") != strings.Join(table.labels, "|") {
+			t.Errorf("Fail %q\nGot: %s %q\nWant: %s %q", table.in, name, name.Labels(), table.out, table.labels)
+		}
+	}
+}
+
+func TestNewNameErrors(t *testing.T) {
+	tables := []struct {
+		in  string
+		err error
+	}{
+		{"a..example.", ErrEmptyLabel},
+		{".example.", ErrEmptyLabel},
+		{strings.Repeat("a", 64) + ".example.", ErrLabelTooLong},
+		{strings.Repeat(strings.Repeat("a", 63)+".", 4), ErrNameTooLong},
+		{`a\9.example.`, ErrBadEscape},
+		{`a\256.example.`, ErrBadEscape},
+	}
+
+	for _, table := range tables {
+		if _, err := NewName(table.in); !errors.Is(err, table.err) {
+			t.Errorf("Fail %q\nGot: %v\nWant: %v", table.in, err, table.err)
+		}
+	}
+
+	if _, err := NameFromLabels([]string{"a", "", "b"}); !errors.Is(err, ErrEmptyLabel) {
+		t.Errorf("Fail\nGot: %v\nWant: %v", err, ErrEmptyLabel)
+	}
+}
+
+func TestNameCompare(t *testing.T) {
+	//RFC 4034 6.1
+	ordered := []string{
+		"example.",
+		"a.example.",
+		"yljkjljk.a.example.",
+		"Z.a.example.",
+		"zABC.a.EXAMPLE.",
+		"z.example.",
+		`\001.z.example.`,
+		"*.z.example.",
+		`\200.z.example.`,
+	}
+
+	names := make([]Name, len(ordered))
+	for i := range ordered {
+		names[len(names)-1-i], _ = NewName(ordered[i])
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		return names[i].Compare(names[j]) < 0
+	})
+
+	for i, name := range names {
+		if name.String() != ordered[i] {
+			t.Errorf("Fail %d\nGot: %s\nWant: %s", i, name, ordered[i])
+		}
+	}
+
+	upper, _ := NewName("WWW.Example.ORG")
+	lower, _ := NewName("www.example.org.")
+	if !upper.Equal(lower) {
+		t.Errorf("Fail\nGot: %s != %s", upper, lower)
+	}
+}
+
+func TestNameHierarchy(t *testing.T) {
+	root, _ := NewName(".")
+	org, _ := NewName("org.")
+	example, _ := NewName("Example.org.")
+	www, _ := NewName("www.example.ORG.")
+	other, _ := NewName("www.example.net.")
+
+	tables := []struct {
+		name        Name
+		parent      Name
+		isSubdomain bool
+		isChild     bool
+	}{
+		{www, example, true, true},
+		{www, org, true, false},
+		{www, root, true, false},
+		{www, www, true, false},
+		{example, www, false, false},
+		{other, example, false, false},
+		{org, root, true, true},
+	}
+
+	for _, table := range tables {
+		if table.name.IsSubdomainOf(table.parent) != table.isSubdomain || table.name.IsChildOf(table.parent) != table.isChild {
+			t.Errorf("Fail %s %s\nGot: %v %v\nWant: %v %v", table.name, table.parent,
+				table.name.IsSubdomainOf(table.parent), table.name.IsChildOf(table.parent), table.isSubdomain, table.isChild)
+		}
+	}
+
+	if !www.Parent().Equal(example) || !org.Parent().IsRoot() || !root.Parent().IsRoot() {
+		t.Errorf("Fail\nGot: %s %s %s", www.Parent(), org.Parent(), root.Parent())
+	}
+}
+
+func TestRecordNames(t *testing.T) {
+	question := Question{Qname: "WWW.example.org", Qtype: DNSRecordTypeA, Qclass: QclassIN}
+	answer := Answer{Name: "www.Example.org.", Type: DNSRecordTypeA, Class: QclassIN}
+
+	qname, err := question.Name()
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
+	owner, err := answer.OwnerName()
+	if err != nil || !owner.Equal(qname) {
+		t.Errorf("Fail\nGot: %s %v\nWant: %s", owner, err, qname)
+	}
+
+	question.Qname = strings.Repeat("a", 64) + ".org"
+	if _, err := question.Name(); !errors.Is(err, ErrLabelTooLong) {
+		t.Errorf("Fail\nGot: %v\nWant: %v", err, ErrLabelTooLong)
+	}
+
+	answer.Name = "www..example.org"
+	if _, err := answer.OwnerName(); !errors.Is(err, ErrEmptyLabel) {
+		t.Errorf("Fail\nGot: %v\nWant: %v", err, ErrEmptyLabel)
+	}
+}
+
+func TestNameWire(t *testing.T) {
+	www, _ := NewName("www.example.org.")
+	dotted, _ := NameFromLabels([]string{"a.b", "example", "org"})
+
+	compression := make(map[string]int)
+	msg := www.Encode(make([]byte, 0), compression)
+	msg = dotted.Encode(msg, compression)
+
+	want := []byte{
+		3, 'w', 'w', 'w', 7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'o', 'r', 'g', 0,
+		3, 'a', '.', 'b', 192, 4,
+	}
+
+	if !bytes.Equal(msg, want) {
+		t.Fatalf("Fail\nGot: %v\nWant: %v", msg, want)
+	}
+
+	tables := []struct {
+		off  int
+		name Name
+		read int
+	}{
+		{0, www, 17},
+		{17, dotted, 6},
+	}
+
+	for _, table := range tables {
+		name, n, err := DecodeName(msg, table.off)
+		if err != nil {
+			t.Errorf("Fail\nGot error: %s", err)
+			continue
+		}
+
+		if !name.Equal(table.name) || n != table.read {
+			t.Errorf("Fail\nGot: %s %d\nWant: %s %d", name, n, table.name, table.read)
+		}
+	}
+
+	var decodeErr *DecodeError
+	if _, _, err := DecodeName([]byte{3, 'w', 'w'}, 0); !errors.As(err, &decodeErr) || !errors.Is(err, ErrLabelOverflow) {
+		t.Errorf("Fail\nGot: %v\nWant: %v", err, ErrLabelOverflow)
+	}
+}
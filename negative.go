@@ -0,0 +1,77 @@
+package dnsPacket
+
+//Negative caching (RFC 2308)
+//
+//A negative response either says the name does not exist at all
+//(NXDOMAIN) or that it exists but has no records of the asked type (NODATA).
+//Both carry the zone's SOA record in the authority section, and may be
+//cached for the smaller of that record's TTL and its MINIMUM field
+
+//Check whether the packet is a NXDOMAIN response: the name does not exist.
+//The rcode is taken with its EDNS bits, so a response with rcode 3 in the
+//header and extended bits in the OPT record is not NXDOMAIN
+func (dns *DNSPacket) IsNXDomain() bool {
+	return dns.QR && dns.ExtendedRcode() == RcodeNameError
+}
+
+//Check whether the packet is a NODATA response: the name exists but has
+//no records of the type asked for. CNAMEs leading to the name may still be
+//in the answer section. Referrals (NS records and no SOA in the authority
+//section) are not NODATA responses
+func (dns *DNSPacket) IsNoData() bool {
+	if !dns.QR || dns.ExtendedRcode() != RcodeNoError || len(dns.Questions) == 0 {
+		return false
+	}
+
+	qtype := dns.Questions[0].Qtype
+	for _, a := range dns.Answers {
+		if a.Type == qtype || a.Type != DNSRecordTypeCNAME {
+			return false
+		}
+	}
+
+	if dns.negativeSOA() != nil {
+		return true
+	}
+
+	for _, a := range dns.Authority {
+		if a.Type == DNSRecordTypeNS {
+			return false
+		}
+	}
+
+	return true
+}
+
+//NegativeTTL returns how long a NXDOMAIN or NODATA response may be
+//cached: the smaller of the TTL of the SOA record in the authority section
+//and its MINIMUM field. ok is false if the packet is not a negative
+//response or carries no SOA record, RFC 2308 says not to cache those
+func (dns *DNSPacket) NegativeTTL() (ttl uint32, ok bool) {
+	if !dns.IsNXDomain() && !dns.IsNoData() {
+		return 0, false
+	}
+
+	answer := dns.negativeSOA()
+	if answer == nil {
+		return 0, false
+	}
+
+	soa := answer.RData.(*RecordTypeSOA)
+	if soa.Minimum < answer.TTL {
+		return soa.Minimum, true
+	}
+
+	return answer.TTL, true
+}
+
+//returns the first SOA record in the authority section
+func (dns *DNSPacket) negativeSOA() *Answer {
+	for i, a := range dns.Authority {
+		if _, ok := a.RData.(*RecordTypeSOA); ok {
+			return &dns.Authority[i]
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+package dnsPacket
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func negativeResponse(rcode Rcode, soaTTL uint32, minimum uint32) DNSPacket {
+	packet := DNSPacket{
+		Header: Header{ID: 1, QR: true, Rcode: rcode},
+	}
+
+	packet.AddQuestion("www.example.org", QclassIN, DNSRecordTypeAAAA)
+	packet.Authority = []Answer{{
+		Name:  "example.org",
+		Type:  DNSRecordTypeSOA,
+		Class: QclassIN,
+		TTL:   soaTTL,
+		RData: &RecordTypeSOA{Mname: "ns.example.org", Rname: "hostmaster.example.org", Minimum: minimum},
+	}}
+
+	return packet
+}
+
+func TestNegativeTTL(t *testing.T) {
+	nxdomain := negativeResponse(RcodeNameError, 3600, 300)
+	nodata := negativeResponse(RcodeNoError, 60, 300)
+
+	//a CNAME pointing to a name without AAAA records is still NODATA
+	cname := negativeResponse(RcodeNoError, 900, 600)
+	cname.Answers = []Answer{{Name: "www.example.org", Type: DNSRecordTypeCNAME, Class: QclassIN, TTL: 60, RData: &RecordTypeCNAME{Target: "host.example.org"}}}
+
+	positive := negativeResponse(RcodeNoError, 60, 300)
+	positive.Answers = []Answer{{Name: "www.example.org", Type: DNSRecordTypeAAAA, Class: QclassIN, TTL: 60, RData: &RecordTypeAAAA{Addr: netip.MustParseAddr("2001:db8::1")}}}
+
+	referral := negativeResponse(RcodeNoError, 0, 0)
+	referral.Authority = []Answer{{Name: "example.org", Type: DNSRecordTypeNS, Class: QclassIN, TTL: 3600, RData: &RecordTypeNS{Host: "ns.example.org"}}}
+
+	//rcode 3 in the header with extended bits is BADMODE, not NXDOMAIN
+	badMode := negativeResponse(RcodeNameError, 3600, 300)
+	badMode.EDNS = NewOPT(DefaultUDPSize)
+	badMode.EDNS.ExtendedRcode = 1
+
+	//BADVERS has rcode 0 in the header
+	badVersion := negativeResponse(RcodeNoError, 60, 300)
+	badVersion.SetExtendedRcode(RcodeBadVersion)
+
+	tables := []struct {
+		name     string
+		packet   DNSPacket
+		nxdomain bool
+		nodata   bool
+		ttl      uint32
+		ok       bool
+	}{
+		{"nxdomain", nxdomain, true, false, 300, true},
+		{"nodata", nodata, false, true, 60, true},
+		{"cname", cname, false, true, 600, true},
+		{"positive", positive, false, false, 0, false},
+		{"referral", referral, false, false, 0, false},
+		{"extended rcode on NXDOMAIN", badMode, false, false, 0, false},
+		{"extended rcode on NOERROR", badVersion, false, false, 0, false},
+	}
+
+	for _, table := range tables {
+		if isNX := table.packet.IsNXDomain(); isNX != table.nxdomain {
+			t.Errorf("%s: Fail\nGot NXDOMAIN: %t\nWant: %t", table.name, isNX, table.nxdomain)
+		}
+
+		if isNoData := table.packet.IsNoData(); isNoData != table.nodata {
+			t.Errorf("%s: Fail\nGot NODATA: %t\nWant: %t", table.name, isNoData, table.nodata)
+		}
+
+		ttl, ok := table.packet.NegativeTTL()
+		if ttl != table.ttl || ok != table.ok {
+			t.Errorf("%s: Fail\nGot: %d %t\nWant: %d %t", table.name, ttl, ok, table.ttl, table.ok)
+		}
+	}
+}
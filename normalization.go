@@ -0,0 +1,148 @@
+package dnsPacket
+
+import (
+	"sort"
+)
+
+//Unicode normalization form C (UAX #15)
+//
+//IDNA only accepts labels in NFC. A string is brought to NFC by fully
+//decomposing it, putting the combining marks following each starter in
+//the canonical order and composing the result again:
+//
+//  "e" U+0301 (COMBINING ACUTE ACCENT)  ->  U+00E9 (é)
+//
+//The decompositions, combining classes and composites are in
+//idnaTables.go, Hangul syllables are decomposed and composed
+//arithmetically
+
+//Hangul syllables (Unicode 3.12)
+const (
+	hangulSBase  = 0xAC00
+	hangulLBase  = 0x1100
+	hangulVBase  = 0x1161
+	hangulTBase  = 0x11A7
+	hangulLCount = 19
+	hangulVCount = 21
+	hangulTCount = 28
+	hangulNCount = hangulVCount * hangulTCount
+	hangulSCount = hangulLCount * hangulNCount
+)
+
+//code points with the same canonical combining class
+type combiningClassRange struct {
+	lo, hi rune
+	class  uint8
+}
+
+//returns s in normalization form C
+func nfc(s string) string {
+	if isASCII(s) {
+		return s
+	}
+
+	return string(compose(decompose([]rune(s))))
+}
+
+//returns the canonical decomposition of runes, in canonical order
+func decompose(runes []rune) []rune {
+	decomposed := make([]rune, 0, len(runes))
+	for _, r := range runes {
+		decomposed = appendDecomposition(decomposed, r)
+	}
+
+	//a stable sort of every run of non-starters by combining class
+	for i := 1; i < len(decomposed); i++ {
+		for j := i; j > 0; j-- {
+			class := combiningClass(decomposed[j])
+			if class == 0 || combiningClass(decomposed[j-1]) <= class {
+				break
+			}
+
+			decomposed[j-1], decomposed[j] = decomposed[j], decomposed[j-1]
+		}
+	}
+
+	return decomposed
+}
+
+//appends the full canonical decomposition of r
+func appendDecomposition(decomposed []rune, r rune) []rune {
+	if r >= hangulSBase && r < hangulSBase+hangulSCount {
+		s := r - hangulSBase
+		decomposed = append(decomposed, hangulLBase+s/hangulNCount, hangulVBase+(s%hangulNCount)/hangulTCount)
+		if t := s % hangulTCount; t != 0 {
+			decomposed = append(decomposed, hangulTBase+t)
+		}
+
+		return decomposed
+	}
+
+	decomposition, ok := decompositions[r]
+	if !ok {
+		return append(decomposed, r)
+	}
+
+	for _, d := range decomposition {
+		decomposed = appendDecomposition(decomposed, d)
+	}
+
+	return decomposed
+}
+
+//composes a string in canonical order: every character that is not
+//blocked from the last starter is combined with it when they have a
+//primary composite
+func compose(runes []rune) []rune {
+	composed := make([]rune, 0, len(runes))
+	starter := -1 //the index of the last starter in composed
+	lastClass := uint8(0)
+
+	for _, r := range runes {
+		class := combiningClass(r)
+
+		//a character between the two with a class of 0 or at least the
+		//class of r blocks it
+		adjacent := starter == len(composed)-1
+		if starter >= 0 && (adjacent || lastClass != 0 && lastClass < class) {
+			if c, ok := composePair(composed[starter], r); ok {
+				composed[starter] = c
+				continue
+			}
+		}
+
+		if class == 0 {
+			starter = len(composed)
+		}
+
+		lastClass = class
+		composed = append(composed, r)
+	}
+
+	return composed
+}
+
+//returns the primary composite of a and b
+func composePair(a rune, b rune) (rune, bool) {
+	switch {
+	case a >= hangulLBase && a < hangulLBase+hangulLCount && b >= hangulVBase && b < hangulVBase+hangulVCount:
+		return hangulSBase + ((a-hangulLBase)*hangulVCount+b-hangulVBase)*hangulTCount, true
+
+	case a >= hangulSBase && a < hangulSBase+hangulSCount && (a-hangulSBase)%hangulTCount == 0 &&
+		b > hangulTBase && b < hangulTBase+hangulTCount:
+		return a + b - hangulTBase, true
+	}
+
+	c, ok := compositions[[2]rune{a, b}]
+
+	return c, ok
+}
+
+func combiningClass(r rune) uint8 {
+	i := sort.Search(len(combiningClasses), func(i int) bool { return combiningClasses[i].hi >= r })
+	if i < len(combiningClasses) && combiningClasses[i].lo <= r {
+		return combiningClasses[i].class
+	}
+
+	return 0
+}
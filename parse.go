@@ -0,0 +1,342 @@
+package dnsPacket
+
+import (
+	"strconv"
+	"strings"
+)
+
+//Presentation format (RFC 1035 5.1)
+//
+//A record is written as
+//
+//  <owner> [<TTL>] [<class>] <type> <RDATA>
+//
+//with the TTL and class in either order. Fields are separated by white
+//space, parentheses let a record span several lines and a semicolon starts
+//a comment. Names not ending in a dot are relative to the origin, "@" is
+//the origin itself. \X and \DDD escape a character or byte anywhere
+
+//RdataParser is implemented by record types that have a presentation
+//format. Parse reads the RDATA fields, quotes removed and escapes kept,
+//and completes relative names with origin. Types without it can only
+//be read in the \# generic form
+type RdataParser interface {
+	Parse(fields []string, origin string) error
+}
+
+//the TTL ParseRR gives records that do not have one
+const defaultTTL = 3600
+
+//a field of a record. quoted fields were written in double quotes
+type token struct {
+	text   string
+	quoted bool
+}
+
+//ParseRR reads a record written in presentation format, for example
+//
+//  _svc._tcp.local. 120 IN SRV 0 5 8080 host.local.
+//
+//Relative names are relative to the root, the TTL defaults to 3600
+//and the class to IN
+func ParseRR(text string) (Answer, error) {
+	return ParseRRWithOrigin(text, ".")
+}
+
+//ParseRRWithOrigin works like ParseRR but completes relative names,
+//the owner's and those in the RDATA, with origin
+func ParseRRWithOrigin(text string, origin string) (Answer, error) {
+	tokens, err := tokenize(text)
+	if err != nil {
+		return Answer{}, &ParseError{Token: text, Err: err}
+	}
+
+	if len(tokens) == 0 {
+		return Answer{}, &ParseError{Token: text, Err: ErrSyntax}
+	}
+
+	origin, err = parseName(origin, ".")
+	if err != nil {
+		return Answer{}, &ParseError{Token: origin, Err: err}
+	}
+
+	name, err := parseName(tokens[0].text, origin)
+	if err != nil {
+		return Answer{}, &ParseError{Token: tokens[0].text, Err: err}
+	}
+
+	answer, _, err := parseRecord(name, defaultTTL, QclassIN, tokens[1:], origin)
+
+	return answer, err
+}
+
+//parses what follows the owner name: the optional TTL and class, the
+//type and the RDATA. ttl and class are used when the record has none,
+//hasTTL tells whether it had its own TTL
+func parseRecord(name string, ttl uint32, class int, tokens []token, origin string) (answer Answer, hasTTL bool, err error) {
+	hasClass := false
+
+	for len(tokens) > 0 {
+		field := tokens[0].text
+
+		if value, err := parseTTL(field); err == nil && !hasTTL && !tokens[0].quoted {
+			ttl = value
+			hasTTL = true
+		} else if value, ok := StringToClass(field); ok && !hasClass {
+			class = value
+			hasClass = true
+		} else {
+			break
+		}
+
+		tokens = tokens[1:]
+	}
+
+	if len(tokens) == 0 {
+		return Answer{}, false, &ParseError{Err: ErrSyntax}
+	}
+
+	rtype, ok := StringToType(tokens[0].text)
+	if !ok {
+		return Answer{}, false, &ParseError{Token: tokens[0].text, Err: ErrUnknownType}
+	}
+
+	fields := make([]string, len(tokens)-1)
+	for i, t := range tokens[1:] {
+		fields[i] = t.text
+	}
+
+	rdata, err := parseRdata(rtype, fields, origin)
+	if err != nil {
+		return Answer{}, false, &ParseError{Token: strings.Join(fields, " "), Err: err}
+	}
+
+	return newRR(name, class, ttl, rdata), hasTTL, nil
+}
+
+//reads the RDATA of a record of type rtype, in the type's own format or
+//the \# generic one. The generic form of a registered type is decoded
+//so the record is typed either way
+func parseRdata(rtype int, fields []string, origin string) (PacketProcessor, error) {
+	rdata := newRData(rtype)
+
+	if len(fields) > 0 && fields[0] == `\#` {
+		data, err := ParseGenericRdata(strings.Join(fields, " "))
+		if err != nil {
+			return nil, err
+		}
+
+		if rdata == nil {
+			return &RecordTypeDefault{Code: rtype, Data: data}, nil
+		}
+
+		if err := rdata.Decode(data, 0, len(data)); err != nil {
+			return nil, err
+		}
+
+		return rdata, nil
+	}
+
+	parser, ok := rdata.(RdataParser)
+	if !ok {
+		return nil, ErrNoPresentationFormat
+	}
+
+	if err := parser.Parse(fields, origin); err != nil {
+		return nil, err
+	}
+
+	return rdata, nil
+}
+
+//splits text into fields. Quoted strings are one field, parentheses
+//only group lines and comments run to the end of the line. Escapes are
+//kept as they are
+func tokenize(text string) ([]token, error) {
+	tokens, depth, err := scanTokens(text, 0)
+	if err == nil && depth != 0 {
+		err = ErrSyntax
+	}
+
+	return tokens, err
+}
+
+//does the work of tokenize for text found depth parentheses deep and
+//returns how deep the end of text is, so a record can be read line by line
+func scanTokens(text string, depth int) ([]token, int, error) {
+	tokens := make([]token, 0)
+	field := new(strings.Builder)
+	inField, quoted := false, false
+
+	end := func() {
+		if inField {
+			tokens = append(tokens, token{text: field.String(), quoted: quoted})
+		}
+
+		field.Reset()
+		inField, quoted = false, false
+	}
+
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+
+		if quoted {
+			switch c {
+			case '\\':
+				if i+1 == len(text) {
+					return nil, depth, ErrSyntax
+				}
+
+				field.WriteByte(c)
+				field.WriteByte(text[i+1])
+				i++
+			case '"':
+				inField = true
+				end()
+			default:
+				field.WriteByte(c)
+			}
+
+			continue
+		}
+
+		switch c {
+		case ' ', '\t', '\r', '\n':
+			end()
+		case ';':
+			end()
+			for i < len(text) && text[i] != '\n' {
+				i++
+			}
+		case '(':
+			end()
+			depth++
+		case ')':
+			end()
+			depth--
+			if depth < 0 {
+				return nil, depth, ErrSyntax
+			}
+		case '"':
+			end()
+			quoted = true
+		case '\\':
+			if i+1 == len(text) {
+				return nil, depth, ErrSyntax
+			}
+
+			field.WriteByte(c)
+			field.WriteByte(text[i+1])
+			inField = true
+			i++
+		default:
+			field.WriteByte(c)
+			inField = true
+		}
+	}
+
+	if quoted {
+		return nil, depth, ErrSyntax
+	}
+
+	end()
+
+	return tokens, depth, nil
+}
+
+//completes a name with origin and checks it. "@" is origin itself.
+//The name is returned without its trailing dot, the way Decode writes names
+func parseName(field string, origin string) (string, error) {
+	if field == "@" {
+		return origin, nil
+	}
+
+	if fqdn(field) != field {
+		if origin != "" && origin != "." {
+			field = field + "." + origin
+		}
+	}
+
+	labels, err := splitLabels(field)
+	if err != nil {
+		return "", err
+	}
+
+	return joinLabels(labels), nil
+}
+
+//reads a TTL given in seconds or as a duration made of numbers followed
+//by s, m, h, d or w, for example 1h30m
+func parseTTL(field string) (uint32, error) {
+	if field == "" {
+		return 0, ErrBadTTL
+	}
+
+	if value, err := strconv.ParseUint(field, 10, 32); err == nil {
+		return uint32(value), nil
+	}
+
+	units := map[byte]uint64{'s': 1, 'm': 60, 'h': 3600, 'd': 86400, 'w': 604800}
+
+	var total, number uint64
+	digits := false
+
+	for i := 0; i < len(field); i++ {
+		c := field[i]
+
+		if c >= '0' && c <= '9' {
+			number = number*10 + uint64(c-'0')
+			digits = true
+		} else if unit, ok := units[c|0x20]; ok && digits {
+			total = total + number*unit
+			number, digits = 0, false
+		} else {
+			return 0, ErrBadTTL
+		}
+
+		if number > 0xFFFFFFFF || total > 0xFFFFFFFF {
+			return 0, ErrBadTTL
+		}
+	}
+
+	if digits {
+		return 0, ErrBadTTL
+	}
+
+	return uint32(total), nil
+}
+
+//reads an unsigned number of at most bits bits
+func parseUint(field string, bits int) (uint64, error) {
+	value, err := strconv.ParseUint(field, 10, bits)
+	if err != nil {
+		return 0, ErrBadRdata
+	}
+
+	return value, nil
+}
+
+//undoes the \X and \DDD escapes of a character-string
+func unescapeText(field string) (string, error) {
+	if !strings.Contains(field, `\`) {
+		return field, nil
+	}
+
+	text := new(strings.Builder)
+	for i := 0; i < len(field); i++ {
+		if field[i] != '\\' {
+			text.WriteByte(field[i])
+			continue
+		}
+
+		b, n, err := unescape(field[i+1:])
+		if err != nil {
+			return "", err
+		}
+
+		text.WriteByte(b)
+		i = i + n
+	}
+
+	return text.String(), nil
+}
@@ -0,0 +1,122 @@
+package dnsPacket
+
+import (
+	"errors"
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestParseRR(t *testing.T) {
+	tables := []struct {
+		text   string
+		origin string
+		name   string
+		ttl    uint32
+		class  int
+		rdata  PacketProcessor
+	}{
+		{"_svc._tcp.local. 120 IN SRV 0 5 8080 host.local.", ".", "_svc._tcp.local", 120, QclassIN, &RecordTypeSRV{Priority: 0, Weight: 5, Port: 8080, Target: "host.local"}},
+		{"www 1h30m IN A 192.0.2.1", "example.org.", "www.example.org", 5400, QclassIN, &RecordTypeA{Addr: netip.MustParseAddr("192.0.2.1")}},
+		{"@ IN 1d MX 10 mail", "example.org", "example.org", 86400, QclassIN, &RecordTypeMX{Preference: 10, Exchange: "mail.example.org"}},
+		{"example.org. AAAA 2001:db8::1", ".", "example.org", defaultTTL, QclassIN, &RecordTypeAAAA{Addr: netip.MustParseAddr("2001:db8::1")}},
+		{`a\.b.example.org. 60 CNAME \065.example.org.`, ".", `a\.b.example.org`, 60, QclassIN, &RecordTypeCNAME{Target: "A.example.org"}},
+		{`example.org. 60 TXT "v=spf1 -all" "say \"hi\"" tab\009here ""`, ".", "example.org", 60, QclassIN, &RecordTypeTXT{Text: []string{"v=spf1 -all", `say "hi"`, "tab\there", ""}}},
+		{`version.bind. 0 CH TXT "9.18"`, ".", "version.bind", 0, QclassCH, &RecordTypeTXT{Text: []string{"9.18"}}},
+		{`host 60 HINFO "x86 64" Linux`, "example.org.", "host.example.org", 60, QclassIN, &RecordTypeHINFO{CPU: "x86 64", OS: "Linux"}},
+		{"1.2.0.192.in-addr.arpa. 60 PTR host.example.org.", ".", "1.2.0.192.in-addr.arpa", 60, QclassIN, &RecordTypePTR{Target: "host.example.org"}},
+		{"example.org. 60 NS ns1", "example.org.", "example.org", 60, QclassIN, &RecordTypeNS{Host: "ns1.example.org"}},
+		{"example.org. 60 A \\# 4 C0000201", ".", "example.org", 60, QclassIN, &RecordTypeA{Addr: netip.MustParseAddr("192.0.2.1")}},
+		{"example.org. 60 CLASS65280 TYPE65281 \\# 3 abcdef", ".", "example.org", 60, 65280, &RecordTypeDefault{Code: 65281, Data: []byte{0xab, 0xcd, 0xef}}},
+		{"example.org. 60 private \\# 2 0102", ".", "example.org", 60, QclassIN, &recordTypePrivate{Value: 258}},
+		{`@ 3600 IN SOA ns hostmaster (
+			2024010101 ; serial
+			2h         ; refresh
+			1h         ; retry
+			2w         ; expire
+			5m )       ; minimum`, "example.org.", "example.org", 3600, QclassIN,
+			&RecordTypeSOA{Mname: "ns.example.org", Rname: "hostmaster.example.org", Serial: 2024010101, Refresh: 7200, Retry: 3600, Expire: 1209600, Minimum: 300}},
+	}
+
+	for _, table := range tables {
+		answer, err := ParseRRWithOrigin(table.text, table.origin)
+		if err != nil {
+			t.Errorf("Fail %q\nGot error: %s", table.text, err)
+			continue
+		}
+
+		if answer.Name != table.name || answer.TTL != table.ttl || answer.Class != table.class || answer.Type != table.rdata.Type() {
+			t.Errorf("Fail %q\nGot: %s %d %d %d\nWant: %s %d %d %d", table.text, answer.Name, answer.TTL, answer.Class, answer.Type, table.name, table.ttl, table.class, table.rdata.Type())
+		}
+
+		if !reflect.DeepEqual(answer.RData, table.rdata) {
+			t.Errorf("Fail %q\nGot: %v\nWant: %v", table.text, answer.RData, table.rdata)
+		}
+
+		//the presentation format reads back to the same record
+		again, err := ParseRR(answer.String())
+		if err != nil || !reflect.DeepEqual(again, answer) {
+			t.Errorf("Fail %q\nGot: %v %v\nWant: %v", answer.String(), again, err, answer)
+		}
+	}
+}
+
+func TestParseRRErrors(t *testing.T) {
+	tables := []struct {
+		text string
+		err  error
+	}{
+		{"", ErrSyntax},
+		{"example.org. 60 IN", ErrSyntax},
+		{`example.org. 60 TXT "open`, ErrSyntax},
+		{"example.org. 60 SOA ( ns hostmaster 1 2 3 4 5", ErrSyntax},
+		{"example.org. 60 IN BOGUS 1", ErrUnknownType},
+		{"example.org. 60 A 2001:db8::1", ErrBadRdata},
+		{"example.org. 60 A 192.0.2.1 192.0.2.2", ErrBadRdata},
+		{"example.org. 60 MX 65536 mail.example.org.", ErrBadRdata},
+		{"example.org. 60 TXT", ErrBadRdata},
+		{"example.org. 60 A \\# 3 C00002", ErrBadRdata},
+		{"example.org. 60 TYPE65281 abcdef", ErrNoPresentationFormat},
+		{"example.org. 60 private 258", ErrNoPresentationFormat},
+		{"example..org. 60 A 192.0.2.1", ErrEmptyLabel},
+		{"example.org. 60 CNAME a\\25.org.", ErrBadEscape},
+		{"example.org. 1x A 192.0.2.1", ErrUnknownType},
+	}
+
+	for _, table := range tables {
+		_, err := ParseRR(table.text)
+
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) || !errors.Is(err, table.err) {
+			t.Errorf("Fail %q\nGot: %v\nWant: %v", table.text, err, table.err)
+		}
+	}
+}
+
+func TestParseTTL(t *testing.T) {
+	tables := []struct {
+		in  string
+		out uint32
+		err error
+	}{
+		{"0", 0, nil},
+		{"3600", 3600, nil},
+		{"1h", 3600, nil},
+		{"1H30M", 5400, nil},
+		{"1w2d3h4m5s", 788645, nil},
+		{"4294967295", 4294967295, nil},
+		{"4294967296", 0, ErrBadTTL},
+		{"1h30", 0, ErrBadTTL},
+		{"h", 0, ErrBadTTL},
+		{"1y", 0, ErrBadTTL},
+		{"", 0, ErrBadTTL},
+	}
+
+	for _, table := range tables {
+		ttl, err := parseTTL(table.in)
+
+		if ttl != table.out || err != table.err {
+			t.Errorf("Fail %q\nGot: %d %v\nWant: %d %v", table.in, ttl, err, table.out, table.err)
+		}
+	}
+}
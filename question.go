@@ -3,7 +3,6 @@ package dnsPacket
 import (
 	"bytes"
 	"fmt"
-	"strings"
 )
 
 type Question struct {
@@ -12,80 +11,175 @@ type Question struct {
 	Qclass int
 }
 
+//Name returns Qname parsed as a Name, with its labels and length checked
+//as Encode checks them
+func (q Question) Name() (Name, error) {
+	return NewName(q.Qname)
+}
+
+//String writes the question the way dig does, as a zone file comment
 func (q Question) String() string {
-	buf := new(bytes.Buffer)
+	return fmt.Sprintf(";%s\t%s\t%s", fqdn(q.Qname), ClassToString(q.Qclass), TypeToString(q.Qtype))
+}
 
-	buf.WriteString(fmt.Sprintf("Name: %s Type: %d Class: %d", q.Qname, q.Qtype, q.Qclass))
+//UnicodeString works like String but writes the A-labels of the name
+//in Unicode, see ToUnicode
+func (q Question) UnicodeString() string {
+	q.Qname = unicodeName(q.Qname)
 
-	return buf.String()
+	return q.String()
 }
 
-func decodeQuestion(packet []byte) (qname string, qtype uint16, qclass uint16, n int) {
-	qname, n = decodeQname(packet)
+//decodes the question starting at msg[off:]. n is how many bytes
+//the question occupies, name, type and class included
+func decodeQuestion(msg []byte, off int) (qname string, qtype uint16, qclass uint16, n int, err error) {
+	qname, n, err = decodeQname(msg, off)
+	if err != nil {
+		return
+	}
 
-	qTypeStart := n
+	qTypeStart := off + n
 	qTypeEnd := qTypeStart + 2
 	qClassStart := qTypeEnd
 	qClassEnd := qTypeEnd + 2
 
-	qtype = decodePart(packet, qTypeStart, qTypeEnd)
-	qclass = decodePart(packet, qClassStart, qClassEnd)
+	if qClassEnd > len(msg) {
+		err = ErrShortBuffer
+		return
+	}
+
+	qtype = decodePart(msg, qTypeStart, qTypeEnd)
+	qclass = decodePart(msg, qClassStart, qClassEnd)
+	n = qClassEnd - off
 
 	return
 
 }
 
+//encodes a name on its own, malformed names encode as nil
 func encodeQname(qname string) []byte {
-	name := make([]byte, 0)
+	encoded, _ := encodeName(make([]byte, 0), qname, nil, false)
+	return encoded
+}
+
+//decodes the name starting at msg[off:] and returns it together with
+//how many bytes it occupies at off. msg must be the whole message so
+//compression pointers (RFC 1035 4.1.4) can be followed wherever they point.
+//The bytes read count stops at the first pointer, since that is where
+//the name ends in the original position
+func decodeQname(msg []byte, off int) (string, int, error) {
+	name := new(bytes.Buffer)
+	start := off
+	n := -1         //bytes read at off, set once the first pointer is followed
+	wireLength := 1 //the terminating zero length label
+	hops := 0
+	visited := make(map[int]bool)
+
+	for {
+		if start < 0 || start >= len(msg) {
+			return "", 0, ErrShortBuffer
+		}
 
-	sections := strings.Split(qname, ".")
+		labelSize := int(msg[start])
 
-	for i := 0; i < len(sections); i++ {
-		length := len(sections[i])
+		switch labelSize & 0xC0 {
+		case 0x00:
+			//a normal label, handled below
 
-		name = append(name, byte(length))
+		case 0xC0:
+			if start+2 > len(msg) {
+				return "", 0, ErrShortBuffer
+			}
 
-		for j := 0; j < length; j++ {
-			name = append(name, byte(sections[i][j]))
-		}
-	}
-	name = append(name, byte(0))
-	return name
-}
+			pointer := int(decodePart(msg, start, start+2) & CompressedAnswerMask)
+			if pointer >= len(msg) {
+				return "", 0, ErrBadPointer
+			}
 
-//returns question name and how many bytes read
-func decodeQname(qname []byte) (string, int) {
-	name := new(bytes.Buffer)
-	start := 0
+			if visited[pointer] {
+				return "", 0, ErrPointerLoop
+			}
 
-	for {
-		labelSize := int(qname[start])
-		label := qname[start+1 : labelSize+start+1]
-		start = start + labelSize + 1
+			hops++
+			if hops > maxPointerHops {
+				return "", 0, ErrTooManyPointers
+			}
+
+			if n < 0 {
+				n = start + 2 - off
+			}
+
+			visited[pointer] = true
+			start = pointer
+			continue
 
-		name.WriteString(string(label))
+		default:
+			//0x40 and 0x80 are reserved label types
+			return "", 0, ErrBadLabelType
+		}
 
-		if qname[start] == 0 {
+		if labelSize == 0 {
 			start++
 			break
 		}
 
-		name.WriteString(".")
+		if start+1+labelSize > len(msg) {
+			return "", 0, ErrLabelOverflow
+		}
+
+		wireLength = wireLength + labelSize + 1
+		if wireLength > maxNameLength {
+			return "", 0, ErrNameTooLong
+		}
+
+		if name.Len() > 0 {
+			name.WriteString(".")
+		}
+
+		label := msg[start+1 : labelSize+start+1]
+		start = start + labelSize + 1
+
+		name.WriteString(escapeLabel(string(label)))
+	}
+
+	if n < 0 {
+		n = start - off
+	}
+
+	return name.String(), n, nil
+}
+
+//decodes RDATA made of a single name, which must fill all length bytes at off
+func decodeNameRdata(msg []byte, off int, length int) (string, error) {
+	name, n, err := decodeQname(msg, off)
+	if err != nil {
+		return "", err
 	}
 
-	return name.String(), start
+	if n != length {
+		return "", ErrBadRdata
+	}
+
+	return name, nil
 }
 
-func encodeQuestion(q Question) []byte {
-	question := make([]byte, 0)
+func encodeQuestion(q Question) ([]byte, error) {
+	return appendQuestion(make([]byte, 0), q, nil)
+}
+
+//appends the question to msg. The name is always written in full
+//but recorded in compression so records can point at it
+func appendQuestion(msg []byte, q Question, compression map[string]int) ([]byte, error) {
+	msg, err := encodeName(msg, q.Qname, compression, false)
+	if err != nil {
+		return nil, err
+	}
 
-	name := encodeQname(q.Qname)
 	qtype, _ := fromIntToBytes(uint16(q.Qtype))
 	qclass, _ := fromIntToBytes(uint16(q.Qclass))
 
-	question = append(question, name...)
-	question = append(question, qtype...)
-	question = append(question, qclass...)
+	msg = append(msg, qtype...)
+	msg = append(msg, qclass...)
 
-	return question
+	return msg, nil
 }
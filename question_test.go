@@ -1,6 +1,8 @@
 package dnsPacket
 
 import (
+	"bytes"
+	"strings"
 	"testing"
 )
 
@@ -21,7 +23,11 @@ func TestDecodeQname(t *testing.T) {
 	}
 
 	for _, table := range tables {
-		decoded, _ := decodeQname(table.in)
+		decoded, _, err := decodeQname(table.in, 0)
+
+		if err != nil {
+			t.Errorf("Fail\nGot error: %s", err)
+		}
 
 		if decoded != table.out {
 			t.Errorf("Fail\nGot: %s\nWant: %s", decoded, table.out)
@@ -29,6 +35,86 @@ func TestDecodeQname(t *testing.T) {
 	}
 }
 
+func TestDecodeCompressedQname(t *testing.T) {
+	//google.com at 0, _tcp.google.com at 12, _http._tcp.google.com at 19
+	msg := []byte{
+		6, 103, 111, 111, 103, 108, 101, 3, 99, 111, 109, 0,
+		4, 95, 116, 99, 112, 192, 0,
+		5, 95, 104, 116, 116, 112, 192, 12,
+		192, 19,
+	}
+
+	tables := []struct {
+		off  int
+		out  string
+		read int
+	}{
+		{0, "google.com", 12},
+		{12, "_tcp.google.com", 7},
+		{19, "_http._tcp.google.com", 8},
+		{27, "_http._tcp.google.com", 2},
+	}
+
+	for _, table := range tables {
+		decoded, n, err := decodeQname(msg, table.off)
+
+		if err != nil {
+			t.Errorf("Fail\nGot error: %s", err)
+		}
+
+		if decoded != table.out || n != table.read {
+			t.Errorf("Fail\nGot: %s %d\nWant: %s %d", decoded, n, table.out, table.read)
+		}
+	}
+}
+
+func TestDecodeMalformedQname(t *testing.T) {
+	long := make([]byte, 0)
+	for i := 0; i < 5; i++ {
+		long = append(long, 63)
+		long = append(long, make([]byte, 63)...)
+	}
+	long = append(long, 0)
+
+	//every pointer points at the one before it, ending at the root name
+	chain := []byte{0}
+	for i := 0; i < 130; i++ {
+		prev := len(chain) - 2
+		if i == 0 {
+			prev = 0
+		}
+		chain = append(chain, byte(192|prev>>8), byte(prev))
+	}
+
+	tables := []struct {
+		in  []byte
+		err error
+	}{
+		{[]byte{192, 0}, ErrPointerLoop},
+		{[]byte{3, 99, 111, 109, 192, 0}, ErrPointerLoop},
+		{[]byte{192, 2, 192, 0}, ErrPointerLoop},
+		{[]byte{192, 9}, ErrBadPointer},
+		{[]byte{192}, ErrShortBuffer},
+		{[]byte{64, 0}, ErrBadLabelType},
+		{[]byte{3, 99, 111}, ErrLabelOverflow},
+		{[]byte{3, 99, 111, 109}, ErrShortBuffer},
+		{long, ErrNameTooLong},
+	}
+
+	for _, table := range tables {
+		_, _, err := decodeQname(table.in, 0)
+
+		if err != table.err {
+			t.Errorf("Fail\nGot: %v\nWant: %v", err, table.err)
+		}
+	}
+
+	_, _, err := decodeQname(chain, len(chain)-2)
+	if err != ErrTooManyPointers {
+		t.Errorf("Fail\nGot: %v\nWant: %v", err, ErrTooManyPointers)
+	}
+}
+
 func TestEncodeQname(t *testing.T) {
 	tables := []struct {
 		out []byte
@@ -58,7 +144,11 @@ func TestEncodeQname(t *testing.T) {
 func TestDecodeQuestion(t *testing.T) {
 	questionBytes := []byte{6, 103, 111, 111, 103, 108, 101, 3, 99, 111, 109, 0, 0, 1, 0, 1}
 
-	qname, qtype, qclass, _ := decodeQuestion(questionBytes)
+	qname, qtype, qclass, n, err := decodeQuestion(questionBytes, 0)
+
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
 
 	if qname != "google.com" {
 		t.Errorf("Fail\nGot: %s\nWant: %s\n", qname, "google.com")
@@ -72,6 +162,10 @@ func TestDecodeQuestion(t *testing.T) {
 		t.Errorf("Fail\nGot: %d\n Want: %d\n", qclass, 1)
 	}
 
+	if n != len(questionBytes) {
+		t.Errorf("Fail\nGot: %d\n Want: %d\n", n, len(questionBytes))
+	}
+
 }
 
 func TestEncodeQuestion(t *testing.T) {
@@ -81,7 +175,11 @@ func TestEncodeQuestion(t *testing.T) {
 		Qtype:  1,
 	}
 
-	questionBytes := encodeQuestion(question)
+	questionBytes, err := encodeQuestion(question)
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
 	expectedBytes := []byte{6, 103, 111, 111, 103, 108, 101, 3, 99, 111, 109, 0, 0, 1, 0, 1}
 
 	for i := range questionBytes {
@@ -90,3 +188,64 @@ func TestEncodeQuestion(t *testing.T) {
 		}
 	}
 }
+
+func TestEncodeEscapedName(t *testing.T) {
+	tables := []struct {
+		in  string
+		out []byte
+	}{
+		{"a\\.b.com", []byte{3, 97, 46, 98, 3, 99, 111, 109, 0}},
+		{"\\065bc.com.", []byte{3, 65, 98, 99, 3, 99, 111, 109, 0}},
+		{"back\\\\slash", []byte{10, 98, 97, 99, 107, 92, 115, 108, 97, 115, 104, 0}},
+		{".", []byte{0}},
+	}
+
+	for _, table := range tables {
+		encoded, err := encodeName(make([]byte, 0), table.in, nil, false)
+		if err != nil {
+			t.Fatalf("Fail\nGot error: %s", err)
+		}
+
+		if !bytes.Equal(encoded, table.out) {
+			t.Errorf("Fail\nGot: %v\nWant: %v", encoded, table.out)
+		}
+	}
+
+	//dots and backslashes inside labels are escaped again when decoding
+	decoded, _, err := decodeQname([]byte{3, 97, 46, 98, 1, 92, 0}, 0)
+	if err != nil || decoded != "a\\.b.\\\\" {
+		t.Errorf("Fail\nGot: %s %v\nWant: %s", decoded, err, "a\\.b.\\\\")
+	}
+}
+
+func TestEncodeMalformedName(t *testing.T) {
+	label := strings.Repeat("a", 63)
+	long := strings.Repeat(label+".", 4)
+
+	tables := []struct {
+		in  string
+		err error
+	}{
+		{"a..com", ErrEmptyLabel},
+		{".com", ErrEmptyLabel},
+		{label + "a.com", ErrLabelTooLong},
+		{long, ErrNameTooLong},
+		{"com\\", ErrBadEscape},
+		{"com\\25", ErrBadEscape},
+		{"com\\256", ErrBadEscape},
+	}
+
+	for _, table := range tables {
+		_, err := encodeName(make([]byte, 0), table.in, nil, false)
+
+		if err != table.err {
+			t.Errorf("Fail %q\nGot: %v\nWant: %v", table.in, err, table.err)
+		}
+	}
+
+	//the longest name that fits: 3 labels of 63 and one of 61
+	longest := strings.Repeat(label+".", 3) + strings.Repeat("a", 61)
+	if _, err := encodeName(make([]byte, 0), longest, nil, false); err != nil {
+		t.Errorf("Fail\nGot error: %s", err)
+	}
+}
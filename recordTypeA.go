@@ -1,48 +1,60 @@
 package dnsPacket
 
 import (
-	"bytes"
-	"strconv"
-	"strings"
+	"fmt"
+	"net/netip"
 )
 
 type RecordTypeA struct {
-	IPv4 string
+	Addr netip.Addr
 }
 
-func (record *RecordTypeA) Process(a Answer) {
-	buf := new(bytes.Buffer)
-
-	//4 bytes (ipv4 address)
-	for i := range a.Data {
-		buf.WriteString(strconv.Itoa(int(a.Data[i])))
+func init() {
+	RegisterType(DNSRecordTypeA, "A", func() PacketProcessor { return &RecordTypeA{} })
+}
 
-		if i <= 2 {
-			buf.WriteString(".")
-		}
+//4 bytes (ipv4 address)
+func (record *RecordTypeA) Decode(msg []byte, off int, length int) error {
+	if length != 4 {
+		return ErrBadRdata
 	}
 
-	record.IPv4 = buf.String()
+	record.Addr = netip.AddrFrom4([4]byte(msg[off : off+4]))
+
+	return nil
 }
 
 func (record *RecordTypeA) Type() int {
-	return 1
+	return DNSRecordTypeA
 }
 
-func (record *RecordTypeA) Encode() []byte {
-	return encodeIpV4(record.IPv4)
+//IPv4-mapped IPv6 addresses are written as the IPv4 address they map
+func (record *RecordTypeA) Encode(msg []byte, compression map[string]int) ([]byte, error) {
+	addr := record.Addr.Unmap()
+	if !addr.Is4() {
+		return nil, fmt.Errorf("dnsPacket: A record needs an IPv4 address, got %q: %w", record.Addr, ErrBadRdata)
+	}
+
+	ip := addr.As4()
+
+	return append(msg, ip[:]...), nil
 }
 
-func encodeIpV4(ip string) []byte {
-	byteIp := make([]byte, 4)
-	parts := strings.Split(ip, ".")
+func (record *RecordTypeA) String() string {
+	return record.Addr.String()
+}
 
-	for i := range parts {
-		part, _ := strconv.ParseInt(parts[i], 10, 16)
-		byteIp[i] = byte(part)
+func (record *RecordTypeA) Parse(fields []string, origin string) error {
+	if len(fields) != 1 {
+		return ErrBadRdata
 	}
 
-	return byteIp
-}
+	addr, err := netip.ParseAddr(fields[0])
+	if err != nil || !addr.Is4() {
+		return ErrBadRdata
+	}
 
-//127.0.0.1
+	record.Addr = addr
+
+	return nil
+}
@@ -0,0 +1,59 @@
+package dnsPacket
+
+import (
+	"fmt"
+	"net/netip"
+)
+
+type RecordTypeAAAA struct {
+	Addr netip.Addr
+}
+
+func init() {
+	RegisterType(DNSRecordTypeAAAA, "AAAA", func() PacketProcessor { return &RecordTypeAAAA{} })
+}
+
+//16 bytes (ipv6 address)
+func (record *RecordTypeAAAA) Decode(msg []byte, off int, length int) error {
+	if length != 16 {
+		return ErrBadRdata
+	}
+
+	record.Addr = netip.AddrFrom16([16]byte(msg[off : off+16]))
+
+	return nil
+}
+
+func (record *RecordTypeAAAA) Type() int {
+	return DNSRecordTypeAAAA
+}
+
+//zones are dropped, they have no meaning outside the host
+func (record *RecordTypeAAAA) Encode(msg []byte, compression map[string]int) ([]byte, error) {
+	if !record.Addr.Is6() {
+		return nil, fmt.Errorf("dnsPacket: AAAA record needs an IPv6 address, got %q: %w", record.Addr, ErrBadRdata)
+	}
+
+	ip := record.Addr.As16()
+
+	return append(msg, ip[:]...), nil
+}
+
+func (record *RecordTypeAAAA) String() string {
+	return record.Addr.String()
+}
+
+func (record *RecordTypeAAAA) Parse(fields []string, origin string) error {
+	if len(fields) != 1 {
+		return ErrBadRdata
+	}
+
+	addr, err := netip.ParseAddr(fields[0])
+	if err != nil || !addr.Is6() || addr.Zone() != "" {
+		return ErrBadRdata
+	}
+
+	record.Addr = addr
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package dnsPacket
+
+import (
+	"bytes"
+	"errors"
+	"net/netip"
+	"reflect"
+	"testing"
+)
+
+func TestAddressRecordsRoundTrip(t *testing.T) {
+	tables := []struct {
+		record  PacketProcessor
+		decoded PacketProcessor
+		data    []byte
+	}{
+		{
+			&RecordTypeA{Addr: netip.MustParseAddr("10.0.0.1")},
+			&RecordTypeA{},
+			[]byte{10, 0, 0, 1},
+		},
+		{
+			&RecordTypeAAAA{Addr: netip.MustParseAddr("2001:db8::1")},
+			&RecordTypeAAAA{},
+			[]byte{32, 1, 13, 184, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1},
+		},
+	}
+
+	for _, table := range tables {
+		data, err := table.record.Encode(nil, nil)
+		if err != nil {
+			t.Fatalf("Fail\nGot error: %s", err)
+		}
+
+		if !bytes.Equal(data, table.data) {
+			t.Errorf("Fail\nGot: %v\nWant: %v", data, table.data)
+		}
+
+		if err := table.decoded.Decode(data, 0, len(data)); err != nil {
+			t.Fatalf("Fail\nGot error: %s", err)
+		}
+
+		if !reflect.DeepEqual(table.decoded, table.record) {
+			t.Errorf("Fail\nGot: %v\nWant: %v", table.decoded, table.record)
+		}
+	}
+}
+
+func TestAddressRecordsValidation(t *testing.T) {
+	//IPv4-mapped addresses are fine for A records
+	data, err := (&RecordTypeA{Addr: netip.MustParseAddr("::ffff:10.0.0.1")}).Encode(nil, nil)
+	if err != nil || !bytes.Equal(data, []byte{10, 0, 0, 1}) {
+		t.Errorf("Fail\nGot: %v %v\nWant: %v", data, err, []byte{10, 0, 0, 1})
+	}
+
+	invalid := []PacketProcessor{
+		&RecordTypeA{},
+		&RecordTypeA{Addr: netip.MustParseAddr("2001:db8::1")},
+		&RecordTypeAAAA{},
+		&RecordTypeAAAA{Addr: netip.MustParseAddr("10.0.0.1")},
+	}
+
+	for _, record := range invalid {
+		if _, err := record.Encode(nil, nil); !errors.Is(err, ErrBadRdata) {
+			t.Errorf("Fail\n%v Got: %v\nWant: %v", record, err, ErrBadRdata)
+		}
+	}
+
+	if err := (&RecordTypeA{}).Decode([]byte{10, 0, 0, 1, 1}, 0, 5); err != ErrBadRdata {
+		t.Errorf("Fail\nGot: %v\nWant: %v", err, ErrBadRdata)
+	}
+
+	if err := (&RecordTypeAAAA{}).Decode([]byte{10, 0, 0, 1}, 0, 4); err != ErrBadRdata {
+		t.Errorf("Fail\nGot: %v\nWant: %v", err, ErrBadRdata)
+	}
+}
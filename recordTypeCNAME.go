@@ -0,0 +1,49 @@
+package dnsPacket
+
+//CNAME makes the owner name an alias of Target
+type RecordTypeCNAME struct {
+	Target string
+}
+
+func init() {
+	RegisterType(DNSRecordTypeCNAME, "CNAME", func() PacketProcessor { return &RecordTypeCNAME{} })
+}
+
+func (record *RecordTypeCNAME) Decode(msg []byte, off int, length int) error {
+	target, err := decodeNameRdata(msg, off, length)
+	if err != nil {
+		return err
+	}
+
+	record.Target = target
+
+	return nil
+}
+
+func (record *RecordTypeCNAME) Type() int {
+	return DNSRecordTypeCNAME
+}
+
+//RFC 1035 lists CNAME among the types whose names may be compressed
+func (record *RecordTypeCNAME) Encode(msg []byte, compression map[string]int) ([]byte, error) {
+	return encodeName(msg, record.Target, compression, true)
+}
+
+func (record *RecordTypeCNAME) String() string {
+	return fqdn(record.Target)
+}
+
+func (record *RecordTypeCNAME) Parse(fields []string, origin string) error {
+	if len(fields) != 1 {
+		return ErrBadRdata
+	}
+
+	name, err := parseName(fields[0], origin)
+	if err != nil {
+		return err
+	}
+
+	record.Target = name
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package dnsPacket
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestNameRecordsCompression(t *testing.T) {
+	//example.org is already in the message at offset 0
+	msg := []byte{7, 101, 120, 97, 109, 112, 108, 101, 3, 111, 114, 103, 0}
+
+	tables := []struct {
+		record  PacketProcessor
+		decoded PacketProcessor
+		data    []byte
+	}{
+		{&RecordTypeCNAME{Target: "www.example.org"}, &RecordTypeCNAME{}, []byte{3, 119, 119, 119, 192, 0}},
+		{&RecordTypeNS{Host: "ns.example.org"}, &RecordTypeNS{}, []byte{2, 110, 115, 192, 0}},
+		{&RecordTypePTR{Target: "example.org"}, &RecordTypePTR{}, []byte{192, 0}},
+		{&RecordTypeMX{Preference: 10, Exchange: "mail.example.org"}, &RecordTypeMX{}, []byte{0, 10, 4, 109, 97, 105, 108, 192, 0}},
+		//DNAME targets are never compressed
+		{&RecordTypeDNAME{Target: "example.org"}, &RecordTypeDNAME{}, []byte{7, 101, 120, 97, 109, 112, 108, 101, 3, 111, 114, 103, 0}},
+	}
+
+	for _, table := range tables {
+		compression := map[string]int{"example.org": 0, "org": 8}
+		start := append([]byte{}, msg...)
+
+		encoded, err := table.record.Encode(start, compression)
+		if err != nil {
+			t.Fatalf("Fail\nGot error: %s", err)
+		}
+
+		if !bytes.Equal(encoded[len(msg):], table.data) {
+			t.Errorf("Fail\nGot: %v\nWant: %v", encoded[len(msg):], table.data)
+		}
+
+		if err := table.decoded.Decode(encoded, len(msg), len(table.data)); err != nil {
+			t.Fatalf("Fail\nGot error: %s", err)
+		}
+
+		if !reflect.DeepEqual(table.decoded, table.record) {
+			t.Errorf("Fail\nGot: %v\nWant: %v", table.decoded, table.record)
+		}
+	}
+}
+
+func TestNameRecordsRejectExtraData(t *testing.T) {
+	//a name followed by a byte that does not belong to it
+	data := []byte{3, 99, 111, 109, 0, 1}
+
+	if err := (&RecordTypeCNAME{}).Decode(data, 0, len(data)); err != ErrBadRdata {
+		t.Errorf("Fail\nGot: %v\nWant: %v", err, ErrBadRdata)
+	}
+}
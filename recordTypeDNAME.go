@@ -0,0 +1,49 @@
+package dnsPacket
+
+//DNAME redirects every name below the owner to the same name below Target
+type RecordTypeDNAME struct {
+	Target string
+}
+
+func init() {
+	RegisterType(DNSRecordTypeDNAME, "DNAME", func() PacketProcessor { return &RecordTypeDNAME{} })
+}
+
+func (record *RecordTypeDNAME) Decode(msg []byte, off int, length int) error {
+	target, err := decodeNameRdata(msg, off, length)
+	if err != nil {
+		return err
+	}
+
+	record.Target = target
+
+	return nil
+}
+
+func (record *RecordTypeDNAME) Type() int {
+	return DNSRecordTypeDNAME
+}
+
+//RFC 6672 does not allow the target to be compressed
+func (record *RecordTypeDNAME) Encode(msg []byte, compression map[string]int) ([]byte, error) {
+	return encodeName(msg, record.Target, compression, false)
+}
+
+func (record *RecordTypeDNAME) String() string {
+	return fqdn(record.Target)
+}
+
+func (record *RecordTypeDNAME) Parse(fields []string, origin string) error {
+	if len(fields) != 1 {
+		return ErrBadRdata
+	}
+
+	name, err := parseName(fields[0], origin)
+	if err != nil {
+		return err
+	}
+
+	record.Target = name
+
+	return nil
+}
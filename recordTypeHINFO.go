@@ -0,0 +1,76 @@
+package dnsPacket
+
+import (
+	"fmt"
+)
+
+type RecordTypeHINFO struct {
+	CPU string
+	OS  string
+}
+
+func init() {
+	RegisterType(DNSRecordTypeHINFO, "HINFO", func() PacketProcessor { return &RecordTypeHINFO{} })
+}
+
+//character-string | character-string
+//CPU              | OS
+func (record *RecordTypeHINFO) Decode(msg []byte, off int, length int) error {
+	end := off + length
+
+	cpu, n, err := decodeCharacterString(msg, off, end)
+	if err != nil {
+		return err
+	}
+
+	os, m, err := decodeCharacterString(msg, off+n, end)
+	if err != nil {
+		return err
+	}
+
+	if n+m != length {
+		return ErrBadRdata
+	}
+
+	record.CPU = cpu
+	record.OS = os
+
+	return nil
+}
+
+func (record *RecordTypeHINFO) Type() int {
+	return DNSRecordTypeHINFO
+}
+
+func (record *RecordTypeHINFO) Encode(msg []byte, compression map[string]int) ([]byte, error) {
+	msg, err := encodeCharacterString(msg, record.CPU)
+	if err != nil {
+		return nil, fmt.Errorf("dnsPacket: HINFO CPU: %w", err)
+	}
+
+	msg, err = encodeCharacterString(msg, record.OS)
+	if err != nil {
+		return nil, fmt.Errorf("dnsPacket: HINFO OS: %w", err)
+	}
+
+	return msg, nil
+}
+
+func (record *RecordTypeHINFO) String() string {
+	return quoteCharacterStrings([]string{record.CPU, record.OS})
+}
+
+func (record *RecordTypeHINFO) Parse(fields []string, origin string) error {
+	if len(fields) != 2 {
+		return ErrBadRdata
+	}
+
+	text, err := parseCharacterStrings(fields)
+	if err != nil {
+		return err
+	}
+
+	record.CPU, record.OS = text[0], text[1]
+
+	return nil
+}
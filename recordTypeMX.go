@@ -0,0 +1,69 @@
+package dnsPacket
+
+import (
+	"fmt"
+)
+
+type RecordTypeMX struct {
+	Preference uint16
+	Exchange   string
+}
+
+func init() {
+	RegisterType(DNSRecordTypeMX, "MX", func() PacketProcessor { return &RecordTypeMX{} })
+}
+
+// 2 bytes      length prefixed labels
+//Preference  | Exchange
+func (record *RecordTypeMX) Decode(msg []byte, off int, length int) error {
+	if length < 3 {
+		return ErrBadRdata
+	}
+
+	exchange, err := decodeNameRdata(msg, off+2, length-2)
+	if err != nil {
+		return err
+	}
+
+	record.Preference = decodePart(msg, off, off+2)
+	record.Exchange = exchange
+
+	return nil
+}
+
+func (record *RecordTypeMX) Type() int {
+	return DNSRecordTypeMX
+}
+
+//RFC 1035 lists MX among the types whose names may be compressed
+func (record *RecordTypeMX) Encode(msg []byte, compression map[string]int) ([]byte, error) {
+	preference, _ := fromIntToBytes(record.Preference)
+
+	msg = append(msg, preference...)
+	return encodeName(msg, record.Exchange, compression, true)
+}
+
+func (record *RecordTypeMX) String() string {
+	return fmt.Sprintf("%d %s", record.Preference, fqdn(record.Exchange))
+}
+
+func (record *RecordTypeMX) Parse(fields []string, origin string) error {
+	if len(fields) != 2 {
+		return ErrBadRdata
+	}
+
+	preference, err := parseUint(fields[0], 16)
+	if err != nil {
+		return err
+	}
+
+	exchange, err := parseName(fields[1], origin)
+	if err != nil {
+		return err
+	}
+
+	record.Preference = uint16(preference)
+	record.Exchange = exchange
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package dnsPacket
+
+//NS names an authoritative name server for the owner name
+type RecordTypeNS struct {
+	Host string
+}
+
+func init() {
+	RegisterType(DNSRecordTypeNS, "NS", func() PacketProcessor { return &RecordTypeNS{} })
+}
+
+func (record *RecordTypeNS) Decode(msg []byte, off int, length int) error {
+	host, err := decodeNameRdata(msg, off, length)
+	if err != nil {
+		return err
+	}
+
+	record.Host = host
+
+	return nil
+}
+
+func (record *RecordTypeNS) Type() int {
+	return DNSRecordTypeNS
+}
+
+//RFC 1035 lists NS among the types whose names may be compressed
+func (record *RecordTypeNS) Encode(msg []byte, compression map[string]int) ([]byte, error) {
+	return encodeName(msg, record.Host, compression, true)
+}
+
+func (record *RecordTypeNS) String() string {
+	return fqdn(record.Host)
+}
+
+func (record *RecordTypeNS) Parse(fields []string, origin string) error {
+	if len(fields) != 1 {
+		return ErrBadRdata
+	}
+
+	name, err := parseName(fields[0], origin)
+	if err != nil {
+		return err
+	}
+
+	record.Host = name
+
+	return nil
+}
@@ -1,17 +1,67 @@
 package dnsPacket
 
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//RecordTypeDefault holds the RDATA of a record type that is not registered.
+//The data is kept exactly as it was received (RFC 3597) so it encodes back
+//byte for byte. Names inside it are never compressed or decompressed
 type RecordTypeDefault struct {
+	Code int
 	Data []byte
 }
 
-func (record *RecordTypeDefault) Process(a Answer) {
-	record.Data = a.Data
+func (record *RecordTypeDefault) Decode(msg []byte, off int, length int) error {
+	record.Data = make([]byte, length)
+	copy(record.Data, msg[off:off+length])
+
+	return nil
 }
 
 func (record *RecordTypeDefault) Type() int {
-	return 0
+	return record.Code
+}
+
+func (record *RecordTypeDefault) Encode(msg []byte, compression map[string]int) ([]byte, error) {
+	return append(msg, record.Data...), nil
+}
+
+//String returns the data in the RFC 3597 generic form: \# <length> <hex>
+func (record *RecordTypeDefault) String() string {
+	if len(record.Data) == 0 {
+		return `\# 0`
+	}
+
+	return fmt.Sprintf(`\# %d %x`, len(record.Data), record.Data)
 }
 
-func (record *RecordTypeDefault) Encode() []byte {
-	return []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+//ParseGenericRdata reads RDATA written in the RFC 3597 generic form,
+//for example `\# 4 0A000001`. The hex may be split by white space.
+//The generic form can be used for any type, known or not
+func ParseGenericRdata(text string) ([]byte, error) {
+	fields := strings.Fields(text)
+
+	if len(fields) < 2 || fields[0] != `\#` {
+		return nil, fmt.Errorf("dnsPacket: %q is not in the \\# <length> <hex> form: %w", text, ErrBadRdata)
+	}
+
+	length, err := strconv.Atoi(fields[1])
+	if err != nil || length < 0 || length > maxRdLength {
+		return nil, fmt.Errorf("dnsPacket: bad length %q in generic rdata: %w", fields[1], ErrBadRdata)
+	}
+
+	data, err := hex.DecodeString(strings.Join(fields[2:], ""))
+	if err != nil {
+		return nil, fmt.Errorf("dnsPacket: bad hex in generic rdata: %w", ErrBadRdata)
+	}
+
+	if len(data) != length {
+		return nil, fmt.Errorf("dnsPacket: generic rdata has %d bytes but length %d: %w", len(data), length, ErrBadRdata)
+	}
+
+	return data, nil
 }
@@ -0,0 +1,56 @@
+package dnsPacket
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestGenericRdataPresentation(t *testing.T) {
+	tables := []struct {
+		data []byte
+		text string
+	}{
+		{[]byte{10, 0, 0, 1}, `\# 4 0a000001`},
+		{[]byte{}, `\# 0`},
+	}
+
+	for _, table := range tables {
+		record := RecordTypeDefault{Code: 65281, Data: table.data}
+
+		if text := record.String(); text != table.text {
+			t.Errorf("Fail\nGot: %s\nWant: %s", text, table.text)
+		}
+
+		data, err := ParseGenericRdata(table.text)
+		if err != nil {
+			t.Errorf("Fail\nGot error: %s", err)
+		}
+
+		if !bytes.Equal(data, table.data) {
+			t.Errorf("Fail\nGot: %v\nWant: %v", data, table.data)
+		}
+	}
+
+	data, err := ParseGenericRdata(`\# 4 0A00 0001`)
+	if err != nil || !bytes.Equal(data, []byte{10, 0, 0, 1}) {
+		t.Errorf("Fail\nGot: %v %v\nWant: %v", data, err, []byte{10, 0, 0, 1})
+	}
+}
+
+func TestParseGenericRdataErrors(t *testing.T) {
+	tables := []string{
+		``,
+		`# 4 0a000001`,
+		`\# four 0a000001`,
+		`\# 4 0a0000`,
+		`\# 4 0a00000g`,
+		`\# 2 0a000001`,
+	}
+
+	for _, table := range tables {
+		if _, err := ParseGenericRdata(table); !errors.Is(err, ErrBadRdata) {
+			t.Errorf("Fail\n%q Got: %v\nWant: %v", table, err, ErrBadRdata)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package dnsPacket
+
+import (
+	"bytes"
+	"fmt"
+)
+
+/*
+EDNS(0) OPT pseudo-record (RFC 6891)
+
+The OPT record reuses the fixed record fields for its own purposes:
+
+NAME   | root (a single zero byte)
+TYPE   | 41
+CLASS  | UDP payload size the sender can receive
+TTL    | EXTENDED-RCODE (8 bits) | VERSION (8 bits) | DO (1 bit) | Z (15 bits)
+RDATA  | options: CODE (2 bytes) | LENGTH (2 bytes) | DATA
+
+Decode takes the OPT record out of the additional section and stores it in
+DNSPacket.EDNS, Encode writes DNSPacket.EDNS as the last additional record.
+*/
+
+//RecordTypeOPT holds the EDNS(0) parameters of a packet.
+//Only Options travel in the RDATA, the other fields are packed into the
+//class and TTL of the record
+type RecordTypeOPT struct {
+	UDPSize       uint16
+	ExtendedRcode uint8
+	Version       uint8
+	DO            bool
+	Z             uint16
+	Options       []EDNSOption
+}
+
+func init() {
+	RegisterType(DNSRecordTypeOPT, "OPT", func() PacketProcessor { return &RecordTypeOPT{} })
+}
+
+//EDNSOption is a single option carried in an OPT record
+type EDNSOption interface {
+	Code() int
+	//Decode reads the option from its data, without code and length
+	Decode(data []byte) error
+	//Encode returns the option data, without code and length
+	Encode() ([]byte, error)
+}
+
+//NewOPT returns EDNS(0) parameters advertising udpSize
+func NewOPT(udpSize uint16) *RecordTypeOPT {
+	return &RecordTypeOPT{UDPSize: udpSize}
+}
+
+//PayloadSize returns the UDP payload size advertised by the sender.
+//RFC 6891 says values below 512 are treated as 512
+func (record *RecordTypeOPT) PayloadSize() int {
+	if record.UDPSize < minUDPSize {
+		return minUDPSize
+	}
+
+	return int(record.UDPSize)
+}
+
+//Option returns the first option with the given code or nil
+func (record *RecordTypeOPT) Option(code int) EDNSOption {
+	for _, option := range record.Options {
+		if option.Code() == code {
+			return option
+		}
+	}
+
+	return nil
+}
+
+func (record *RecordTypeOPT) Decode(msg []byte, off int, length int) error {
+	end := off + length
+	options := make([]EDNSOption, 0)
+
+	for off < end {
+		if off+4 > end {
+			return ErrBadRdata
+		}
+
+		code := int(decodePart(msg, off, off+2))
+		optionLength := int(decodePart(msg, off+2, off+4))
+		if off+4+optionLength > end {
+			return ErrBadRdata
+		}
+
+		//a malformed option is kept as received rather than failing the
+		//whole packet, so the rest of the reply can still be used
+		data := msg[off+4 : off+4+optionLength]
+		option := newEDNSOption(code)
+		if err := option.Decode(data); err != nil {
+			option = &EDNSOptionDefault{OptionCode: code}
+			option.Decode(data)
+		}
+
+		options = append(options, option)
+		off = off + 4 + optionLength
+	}
+
+	record.Options = options
+
+	return nil
+}
+
+func (record *RecordTypeOPT) Type() int {
+	return DNSRecordTypeOPT
+}
+
+func (record *RecordTypeOPT) Encode(msg []byte, compression map[string]int) ([]byte, error) {
+	for _, option := range record.Options {
+		data, err := option.Encode()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(data) > maxRdLength {
+			return nil, fmt.Errorf("dnsPacket: EDNS option %d: %w", option.Code(), ErrRdataTooLong)
+		}
+
+		code, _ := fromIntToBytes(uint16(option.Code()))
+		length, _ := fromIntToBytes(uint16(len(data)))
+
+		msg = append(msg, code...)
+		msg = append(msg, length...)
+		msg = append(msg, data...)
+	}
+
+	return msg, nil
+}
+
+//returns the OPT record as it is written to the additional section
+func (record *RecordTypeOPT) answer() Answer {
+	ttl := uint32(record.ExtendedRcode)<<24 | uint32(record.Version)<<16 | uint32(record.Z&ednsZMask)
+	if record.DO {
+		ttl = ttl | ednsDOMask
+	}
+
+	return Answer{
+		Name:  "",
+		Type:  DNSRecordTypeOPT,
+		Class: int(record.UDPSize),
+		TTL:   ttl,
+		RData: record,
+	}
+}
+
+//fills in the fields packed into the class and TTL of the OPT record a
+func (record *RecordTypeOPT) fromAnswer(a Answer) {
+	record.UDPSize = uint16(a.Class)
+	record.ExtendedRcode = uint8(a.TTL >> 24)
+	record.Version = uint8(a.TTL >> 16)
+	record.DO = a.TTL&ednsDOMask != 0
+	record.Z = uint16(a.TTL & ednsZMask)
+}
+
+//EDNSOptionDefault holds an option this package has no type for, or a
+//known option whose data is malformed.
+//Its data is kept as is so it encodes back unchanged
+type EDNSOptionDefault struct {
+	OptionCode int
+	Data       []byte
+}
+
+func (option *EDNSOptionDefault) Code() int {
+	return option.OptionCode
+}
+
+func (option *EDNSOptionDefault) Decode(data []byte) error {
+	option.Data = make([]byte, len(data))
+	copy(option.Data, data)
+
+	return nil
+}
+
+func (option *EDNSOptionDefault) Encode() ([]byte, error) {
+	return option.Data, nil
+}
+
+func (option *EDNSOptionDefault) String() string {
+	return fmt.Sprintf("%x", option.Data)
+}
+
+var ednsOptionNames = map[int]string{
+	EDNSOptionCodeNSID:      "NSID",
+	EDNSOptionCodeSubnet:    "CLIENT-SUBNET",
+	EDNSOptionCodeCookie:    "COOKIE",
+	EDNSOptionCodeKeepalive: "TCP-KEEPALIVE",
+	EDNSOptionCodePadding:   "PADDING",
+	EDNSOptionCodeEDE:       "EDE",
+}
+
+//String writes the OPT pseudosection the way dig does, one line for the
+//EDNS header fields and one per option
+func (record *RecordTypeOPT) String() string {
+	buf := new(bytes.Buffer)
+
+	flags := ""
+	if record.DO {
+		flags = " do"
+	}
+
+	buf.WriteString(fmt.Sprintf("; EDNS: version: %d, flags:%s; udp: %d", record.Version, flags, record.UDPSize))
+	if record.Z != 0 {
+		buf.WriteString(fmt.Sprintf("; MBZ: 0x%04x", record.Z))
+	}
+
+	for _, option := range record.Options {
+		name, ok := ednsOptionNames[option.Code()]
+		if !ok {
+			name = fmt.Sprintf("OPT=%d", option.Code())
+		}
+
+		buf.WriteString(fmt.Sprintf("\n; %s: %s", name, option))
+	}
+
+	return buf.String()
+}
+
+//returns an empty option for code
+func newEDNSOption(code int) EDNSOption {
+	switch code {
+	case EDNSOptionCodeNSID:
+		return &EDNSOptionNSID{}
+
+	case EDNSOptionCodeSubnet:
+		return &EDNSOptionSubnet{}
+
+	case EDNSOptionCodeCookie:
+		return &EDNSOptionCookie{}
+
+	case EDNSOptionCodeKeepalive:
+		return &EDNSOptionKeepalive{}
+
+	case EDNSOptionCodePadding:
+		return &EDNSOptionPadding{}
+
+	case EDNSOptionCodeEDE:
+		return &EDNSOptionEDE{}
+	}
+
+	return &EDNSOptionDefault{OptionCode: code}
+}
@@ -0,0 +1,49 @@
+package dnsPacket
+
+//PTR points to another name, usually from the reverse tree
+type RecordTypePTR struct {
+	Target string
+}
+
+func init() {
+	RegisterType(DNSRecordTypePTR, "PTR", func() PacketProcessor { return &RecordTypePTR{} })
+}
+
+func (record *RecordTypePTR) Decode(msg []byte, off int, length int) error {
+	target, err := decodeNameRdata(msg, off, length)
+	if err != nil {
+		return err
+	}
+
+	record.Target = target
+
+	return nil
+}
+
+func (record *RecordTypePTR) Type() int {
+	return DNSRecordTypePTR
+}
+
+//RFC 1035 lists PTR among the types whose names may be compressed
+func (record *RecordTypePTR) Encode(msg []byte, compression map[string]int) ([]byte, error) {
+	return encodeName(msg, record.Target, compression, true)
+}
+
+func (record *RecordTypePTR) String() string {
+	return fqdn(record.Target)
+}
+
+func (record *RecordTypePTR) Parse(fields []string, origin string) error {
+	if len(fields) != 1 {
+		return ErrBadRdata
+	}
+
+	name, err := parseName(fields[0], origin)
+	if err != nil {
+		return err
+	}
+
+	record.Target = name
+
+	return nil
+}
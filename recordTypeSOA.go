@@ -0,0 +1,150 @@
+package dnsPacket
+
+import (
+	"fmt"
+)
+
+//SOA marks the start of a zone of authority. Besides the zone's primary
+//name server and contact mailbox it holds the timers secondaries use and,
+//in Minimum, the upper bound for caching negative answers (RFC 2308)
+type RecordTypeSOA struct {
+	Mname   string
+	Rname   string
+	Serial  uint32
+	Refresh uint32
+	Retry   uint32
+	Expire  uint32
+	Minimum uint32
+}
+
+func init() {
+	RegisterType(DNSRecordTypeSOA, "SOA", func() PacketProcessor { return &RecordTypeSOA{} })
+}
+
+//labels  | labels  | 4 bytes | 4 bytes | 4 bytes | 4 bytes | 4 bytes
+//MNAME   | RNAME   | SERIAL  | REFRESH | RETRY   | EXPIRE  | MINIMUM
+func (record *RecordTypeSOA) Decode(msg []byte, off int, length int) error {
+	end := off + length
+
+	mname, n, err := decodeQname(msg, off)
+	if err != nil {
+		return err
+	}
+
+	rname, m, err := decodeQname(msg, off+n)
+	if err != nil {
+		return err
+	}
+
+	start := off + n + m
+	if start+20 != end {
+		return ErrBadRdata
+	}
+
+	record.Mname = mname
+	record.Rname = rname
+	record.Serial = decodeUint32(msg, start)
+	record.Refresh = decodeUint32(msg, start+4)
+	record.Retry = decodeUint32(msg, start+8)
+	record.Expire = decodeUint32(msg, start+12)
+	record.Minimum = decodeUint32(msg, start+16)
+
+	return nil
+}
+
+func (record *RecordTypeSOA) Type() int {
+	return DNSRecordTypeSOA
+}
+
+//RFC 1035 lists SOA among the types whose names may be compressed
+func (record *RecordTypeSOA) Encode(msg []byte, compression map[string]int) ([]byte, error) {
+	msg, err := encodeName(msg, record.Mname, compression, true)
+	if err != nil {
+		return nil, err
+	}
+
+	msg, err = encodeName(msg, record.Rname, compression, true)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, value := range []uint32{record.Serial, record.Refresh, record.Retry, record.Expire, record.Minimum} {
+		valueBytes, _ := fromUint32ToBytes(value)
+		msg = append(msg, valueBytes...)
+	}
+
+	return msg, nil
+}
+
+func (record *RecordTypeSOA) String() string {
+	return fmt.Sprintf("%s %s %d %d %d %d %d", fqdn(record.Mname), fqdn(record.Rname), record.Serial, record.Refresh, record.Retry, record.Expire, record.Minimum)
+}
+
+//the timers may be written as durations, like TTLs
+func (record *RecordTypeSOA) Parse(fields []string, origin string) error {
+	if len(fields) != 7 {
+		return ErrBadRdata
+	}
+
+	mname, err := parseName(fields[0], origin)
+	if err != nil {
+		return err
+	}
+
+	rname, err := parseName(fields[1], origin)
+	if err != nil {
+		return err
+	}
+
+	serial, err := parseUint(fields[2], 32)
+	if err != nil {
+		return err
+	}
+
+	timers := make([]uint32, 4)
+	for i := range timers {
+		timers[i], err = parseTTL(fields[3+i])
+		if err != nil {
+			return ErrBadRdata
+		}
+	}
+
+	record.Mname, record.Rname = mname, rname
+	record.Serial = uint32(serial)
+	record.Refresh, record.Retry, record.Expire, record.Minimum = timers[0], timers[1], timers[2], timers[3]
+
+	return nil
+}
+
+//Serial number arithmetic (RFC 1982) with SERIAL_BITS = 32.
+//Serials wrap around, so 1 comes after 4294967295
+
+const maxSerialIncrement = 1<<31 - 1
+
+//SerialAdd adds n to serial. n may be at most 2^31 - 1
+func SerialAdd(serial uint32, n uint32) (uint32, error) {
+	if n > maxSerialIncrement {
+		return serial, fmt.Errorf("dnsPacket: cannot add %d to a serial, the maximum is %d", n, maxSerialIncrement)
+	}
+
+	return serial + n, nil
+}
+
+//SerialCompare returns -1 if a comes before b, 0 if they are equal and 1 if
+//a comes after b. ok is false when a and b are exactly 2^31 apart, where
+//RFC 1982 leaves the order undefined
+func SerialCompare(a uint32, b uint32) (result int, ok bool) {
+	switch {
+	case a == b:
+		return 0, true
+
+	case b-a == 1<<31:
+		return 0, false
+
+	case b-a < 1<<31:
+		return -1, true
+
+	default:
+		return 1, true
+	}
+}
@@ -0,0 +1,74 @@
+package dnsPacket
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSOARoundTrip(t *testing.T) {
+	packet := DNSPacket{
+		Header: Header{ID: 1, QR: true, Rcode: RcodeNameError},
+	}
+
+	packet.AddQuestion("nope.example.org", QclassIN, DNSRecordTypeA)
+	soa := &RecordTypeSOA{
+		Mname:   "ns.example.org",
+		Rname:   "hostmaster.example.org",
+		Serial:  2024010101,
+		Refresh: 7200,
+		Retry:   3600,
+		Expire:  1209600,
+		Minimum: 300,
+	}
+	packet.Authority = []Answer{{Name: "example.org", Type: DNSRecordTypeSOA, Class: QclassIN, TTL: 3600, RData: soa}}
+
+	data, err := Encode(&packet)
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
+	decoded, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
+	if !reflect.DeepEqual(decoded.Authority[0].RData, soa) {
+		t.Errorf("Fail\nGot: %v\nWant: %v", decoded.Authority[0].RData, soa)
+	}
+}
+
+func TestSerialArithmetic(t *testing.T) {
+	tables := []struct {
+		a      uint32
+		b      uint32
+		result int
+		ok     bool
+	}{
+		{1, 1, 0, true},
+		{1, 2, -1, true},
+		{2, 1, 1, true},
+		{4294967295, 0, -1, true},
+		{0, 4294967295, 1, true},
+		{4294967295, 1, -1, true},
+		{0, 1 << 31, 0, false},
+		{1 << 31, 0, 0, false},
+		{0, 1<<31 - 1, -1, true},
+		{0, 1<<31 + 1, 1, true},
+	}
+
+	for _, table := range tables {
+		result, ok := SerialCompare(table.a, table.b)
+
+		if result != table.result || ok != table.ok {
+			t.Errorf("Fail %d %d\nGot: %d %t\nWant: %d %t", table.a, table.b, result, ok, table.result, table.ok)
+		}
+	}
+
+	if serial, err := SerialAdd(4294967295, 2); err != nil || serial != 1 {
+		t.Errorf("Fail\nGot: %d %v\nWant: %d", serial, err, 1)
+	}
+
+	if _, err := SerialAdd(1, 1<<31); err == nil {
+		t.Errorf("Fail\nGot: nil\nWant: error for an increment of 2^31")
+	}
+}
@@ -0,0 +1,45 @@
+package dnsPacket
+
+//SPF has the same format as TXT (RFC 4408). The type is obsolete,
+//SPF policies are published in TXT records nowadays
+type RecordTypeSPF struct {
+	Text []string
+}
+
+func init() {
+	RegisterType(DNSRecordTypeSPF, "SPF", func() PacketProcessor { return &RecordTypeSPF{} })
+}
+
+func (record *RecordTypeSPF) Decode(msg []byte, off int, length int) error {
+	text, err := decodeCharacterStrings(msg, off, length)
+	if err != nil {
+		return err
+	}
+
+	record.Text = text
+
+	return nil
+}
+
+func (record *RecordTypeSPF) Type() int {
+	return DNSRecordTypeSPF
+}
+
+func (record *RecordTypeSPF) Encode(msg []byte, compression map[string]int) ([]byte, error) {
+	return encodeCharacterStrings(msg, record.Text), nil
+}
+
+func (record *RecordTypeSPF) String() string {
+	return quoteCharacterStrings(record.Text)
+}
+
+func (record *RecordTypeSPF) Parse(fields []string, origin string) error {
+	text, err := parseCharacterStrings(fields)
+	if err != nil {
+		return err
+	}
+
+	record.Text = text
+
+	return nil
+}
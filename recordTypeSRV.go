@@ -1,5 +1,9 @@
 package dnsPacket
 
+import (
+	"fmt"
+)
+
 type RecordTypeSRV struct {
 	Priority uint16
 	Weight   uint16
@@ -7,38 +11,82 @@ type RecordTypeSRV struct {
 	Target   string
 }
 
+func init() {
+	RegisterType(DNSRecordTypeSRV, "SRV", func() PacketProcessor { return &RecordTypeSRV{} })
+}
+
 // 2 bytes   2 bytes    2 bytes length prefixed labels
 //Priority  | Weight  | Port   | Target
 //
-func (record *RecordTypeSRV) Process(a Answer) {
+//The target may be compressed by the sender, so it is decoded against the whole message
+func (record *RecordTypeSRV) Decode(msg []byte, off int, length int) error {
+	if length < 7 {
+		return ErrBadRdata
+	}
+
+	priority := decodePart(msg, off, off+2)
+	weight := decodePart(msg, off+2, off+4)
+	port := decodePart(msg, off+4, off+6)
 
-	priority := decodePart(a.Data, 0, 2)
-	weight := decodePart(a.Data, 2, 4)
-	port := decodePart(a.Data, 4, 6)
+	target, n, err := decodeQname(msg, off+6)
+	if err != nil {
+		return err
+	}
 
-	target, _ := decodeQname(a.Data[6:])
+	if 6+n != length {
+		return ErrBadRdata
+	}
 
 	record.Priority = priority
 	record.Weight = weight
 	record.Port = port
 	record.Target = target
+
+	return nil
 }
 
 func (record *RecordTypeSRV) Type() int {
-	return 33
+	return DNSRecordTypeSRV
 }
 
-func (record *RecordTypeSRV) Encode() []byte {
-	data := make([]byte, 0)
-
+//RFC 2782 does not allow the target to be compressed
+func (record *RecordTypeSRV) Encode(msg []byte, compression map[string]int) ([]byte, error) {
 	priorityBytes, _ := fromIntToBytes(record.Priority)
 	weightBytes, _ := fromIntToBytes(record.Weight)
 	portBytes, _ := fromIntToBytes(record.Port)
 
-	data = append(data, priorityBytes...)
-	data = append(data, weightBytes...)
-	data = append(data, portBytes...)
-	data = append(data, encodeQname(record.Target)...)
+	msg = append(msg, priorityBytes...)
+	msg = append(msg, weightBytes...)
+	msg = append(msg, portBytes...)
+	return encodeName(msg, record.Target, compression, false)
+}
+
+func (record *RecordTypeSRV) String() string {
+	return fmt.Sprintf("%d %d %d %s", record.Priority, record.Weight, record.Port, fqdn(record.Target))
+}
+
+func (record *RecordTypeSRV) Parse(fields []string, origin string) error {
+	if len(fields) != 4 {
+		return ErrBadRdata
+	}
+
+	values := make([]uint16, 3)
+	for i := range values {
+		value, err := parseUint(fields[i], 16)
+		if err != nil {
+			return err
+		}
+
+		values[i] = uint16(value)
+	}
+
+	target, err := parseName(fields[3], origin)
+	if err != nil {
+		return err
+	}
+
+	record.Priority, record.Weight, record.Port = values[0], values[1], values[2]
+	record.Target = target
 
-	return data
+	return nil
 }
@@ -0,0 +1,46 @@
+package dnsPacket
+
+//TXT holds one or more strings. Strings longer than 255 bytes are
+//split into several character-strings when encoding, decoding does not
+//join them back since the split is part of the record
+type RecordTypeTXT struct {
+	Text []string
+}
+
+func init() {
+	RegisterType(DNSRecordTypeTXT, "TXT", func() PacketProcessor { return &RecordTypeTXT{} })
+}
+
+func (record *RecordTypeTXT) Decode(msg []byte, off int, length int) error {
+	text, err := decodeCharacterStrings(msg, off, length)
+	if err != nil {
+		return err
+	}
+
+	record.Text = text
+
+	return nil
+}
+
+func (record *RecordTypeTXT) Type() int {
+	return DNSRecordTypeTXT
+}
+
+func (record *RecordTypeTXT) Encode(msg []byte, compression map[string]int) ([]byte, error) {
+	return encodeCharacterStrings(msg, record.Text), nil
+}
+
+func (record *RecordTypeTXT) String() string {
+	return quoteCharacterStrings(record.Text)
+}
+
+func (record *RecordTypeTXT) Parse(fields []string, origin string) error {
+	text, err := parseCharacterStrings(fields)
+	if err != nil {
+		return err
+	}
+
+	record.Text = text
+
+	return nil
+}
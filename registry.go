@@ -0,0 +1,108 @@
+package dnsPacket
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//The record type registry maps a type code to its mnemonic ("A", "SRV"...)
+//and to a factory returning an empty PacketProcessor for it.
+//Decode uses it to pick the typed record for RData and the presentation
+//format uses it to print and read type names. Built in types register
+//themselves from their own files the same way outside types do
+
+type typeEntry struct {
+	mnemonic string
+	factory  func() PacketProcessor
+}
+
+var (
+	registryLock sync.RWMutex
+	typesByCode  = make(map[int]typeEntry)
+	typesByName  = make(map[string]int)
+)
+
+//RegisterType makes a record type known to the package. factory must
+//return a new, empty record each time it is called.
+//Types in the private use range 65280-65534 can be registered like any other.
+//RegisterType panics if code or mnemonic is already registered, like
+//database/sql.Register does, since that is always a programming error
+func RegisterType(code int, mnemonic string, factory func() PacketProcessor) {
+	if code <= 0 || code > 0xFFFF {
+		panic(fmt.Sprintf("dnsPacket: RegisterType of invalid type code %d", code))
+	}
+
+	if mnemonic == "" || factory == nil {
+		panic(fmt.Sprintf("dnsPacket: RegisterType of type %d without mnemonic or factory", code))
+	}
+
+	mnemonic = strings.ToUpper(mnemonic)
+
+	registryLock.Lock()
+	defer registryLock.Unlock()
+
+	if _, ok := typesByCode[code]; ok {
+		panic(fmt.Sprintf("dnsPacket: RegisterType called twice for type %d", code))
+	}
+
+	if _, ok := typesByName[mnemonic]; ok {
+		panic(fmt.Sprintf("dnsPacket: RegisterType called twice for %s", mnemonic))
+	}
+
+	typesByCode[code] = typeEntry{mnemonic: mnemonic, factory: factory}
+	typesByName[mnemonic] = code
+}
+
+//TypeToString returns the mnemonic of a type code. Unregistered types
+//are written TYPEnnn as described in RFC 3597
+func TypeToString(code int) string {
+	registryLock.RLock()
+	entry, ok := typesByCode[code]
+	registryLock.RUnlock()
+
+	if ok {
+		return entry.mnemonic
+	}
+
+	return "TYPE" + strconv.Itoa(code)
+}
+
+//StringToType returns the type code for a mnemonic, ignoring case.
+//The RFC 3597 TYPEnnn form is accepted for any type
+func StringToType(mnemonic string) (int, bool) {
+	mnemonic = strings.ToUpper(mnemonic)
+
+	registryLock.RLock()
+	code, ok := typesByName[mnemonic]
+	registryLock.RUnlock()
+
+	if ok {
+		return code, true
+	}
+
+	if !strings.HasPrefix(mnemonic, "TYPE") {
+		return 0, false
+	}
+
+	code, err := strconv.Atoi(mnemonic[4:])
+	if err != nil || code < 0 || code > 0xFFFF {
+		return 0, false
+	}
+
+	return code, true
+}
+
+//returns an empty typed record for rtype or nil if the type is not registered
+func newRData(rtype int) PacketProcessor {
+	registryLock.RLock()
+	entry, ok := typesByCode[rtype]
+	registryLock.RUnlock()
+
+	if !ok {
+		return nil
+	}
+
+	return entry.factory()
+}
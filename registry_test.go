@@ -0,0 +1,101 @@
+package dnsPacket
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testPrivateType = 65280
+
+//a private use record holding a single 16 bit value
+type recordTypePrivate struct {
+	Value uint16
+}
+
+func (record *recordTypePrivate) Decode(msg []byte, off int, length int) error {
+	if length != 2 {
+		return ErrBadRdata
+	}
+
+	record.Value = decodePart(msg, off, off+2)
+
+	return nil
+}
+
+func (record *recordTypePrivate) Encode(msg []byte, compression map[string]int) ([]byte, error) {
+	value, _ := fromIntToBytes(record.Value)
+
+	return append(msg, value...), nil
+}
+
+func (record *recordTypePrivate) Type() int {
+	return testPrivateType
+}
+
+func init() {
+	RegisterType(testPrivateType, "private", func() PacketProcessor { return &recordTypePrivate{} })
+}
+
+func TestRegisteredTypeRoundTrip(t *testing.T) {
+	packet := DNSPacket{
+		Header: Header{ID: 1, QR: true},
+	}
+
+	packet.Answers = []Answer{
+		{Name: "example.org", Type: testPrivateType, Class: 1, TTL: 60, RdLength: 2, RData: &recordTypePrivate{Value: 1234}},
+	}
+
+	data, err := Encode(&packet)
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
+	decoded, err := Decode(data)
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
+	if !reflect.DeepEqual(decoded.Answers, packet.Answers) {
+		t.Errorf("Fail\nGot: %v\nWant: %v", decoded.Answers, packet.Answers)
+	}
+}
+
+func TestTypeMnemonics(t *testing.T) {
+	tables := []struct {
+		code     int
+		mnemonic string
+	}{
+		{DNSRecordTypeA, "A"},
+		{DNSRecordTypeSRV, "SRV"},
+		{testPrivateType, "PRIVATE"},
+		{65281, "TYPE65281"},
+	}
+
+	for _, table := range tables {
+		if mnemonic := TypeToString(table.code); mnemonic != table.mnemonic {
+			t.Errorf("Fail\nGot: %s\nWant: %s", mnemonic, table.mnemonic)
+		}
+
+		if code, ok := StringToType(table.mnemonic); !ok || code != table.code {
+			t.Errorf("Fail\nGot: %d %t\nWant: %d", code, ok, table.code)
+		}
+	}
+
+	if code, ok := StringToType("srv"); !ok || code != DNSRecordTypeSRV {
+		t.Errorf("Fail\nGot: %d %t\nWant: %d", code, ok, DNSRecordTypeSRV)
+	}
+
+	if _, ok := StringToType("NOPE"); ok {
+		t.Errorf("Fail\nGot: ok\nWant: unknown mnemonic")
+	}
+}
+
+func TestRegisterTypeTwicePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("Fail\nRegisterType of a known type did not panic")
+		}
+	}()
+
+	RegisterType(DNSRecordTypeA, "A2", func() PacketProcessor { return &RecordTypeA{} })
+}
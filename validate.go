@@ -0,0 +1,52 @@
+package dnsPacket
+
+import (
+	"fmt"
+)
+
+//Validate checks the packet against the structural rules of RFC 1035 and
+//returns the first problem found. It rejects everything EncodeStrict
+//rejects (opcode and rcode ranges, section lengths, header counts that do
+//not match their sections with the OPT record counted in Arcount, label
+//and name lengths) and on top of that wants the reserved Z bit clear and,
+//for the QUERY opcode, at most one question (RFC 9619), which a NOERROR
+//or NXDOMAIN response must echo. Decode accepts packets breaking these
+//last rules, since they are still found on the wire
+func (dns *DNSPacket) Validate() error {
+	for _, q := range dns.Questions {
+		if _, err := q.Name(); err != nil {
+			return fmt.Errorf("dnsPacket: question %q: %w", q.Qname, err)
+		}
+	}
+
+	for _, section := range [][]Answer{dns.Answers, dns.Authority, dns.Additional} {
+		for _, a := range section {
+			if _, err := a.OwnerName(); err != nil {
+				return fmt.Errorf("dnsPacket: owner name %q: %w", a.Name, err)
+			}
+		}
+	}
+
+	if _, err := EncodeStrict(dns); err != nil {
+		return err
+	}
+
+	if dns.Z {
+		return fmt.Errorf("dnsPacket: %w", ErrReservedBit)
+	}
+
+	if dns.Opcode != OpcodeStandardQuery {
+		return nil
+	}
+
+	if len(dns.Questions) > 1 {
+		return fmt.Errorf("dnsPacket: %d questions: %w", len(dns.Questions), ErrTooManyQuestions)
+	}
+
+	rcode := dns.ExtendedRcode()
+	if dns.QR && len(dns.Questions) == 0 && (rcode == RcodeNoError || rcode == RcodeNameError) {
+		return fmt.Errorf("dnsPacket: %s response: %w", rcode, ErrMissingQuestion)
+	}
+
+	return nil
+}
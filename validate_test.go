@@ -0,0 +1,110 @@
+package dnsPacket
+
+import (
+	"errors"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	query := func() DNSPacket {
+		packet := DNSPacket{Header: Header{ID: 1, RD: true}}
+		packet.AddQuestion("example.org", QclassIN, DNSRecordTypeA)
+		return packet
+	}
+
+	valid := query()
+
+	badOpcode := query()
+	badOpcode.Opcode = 16
+
+	badRcode := query()
+	badRcode.Rcode = RcodeBadCookie
+
+	reserved := query()
+	reserved.Z = true
+
+	twoQuestions := query()
+	twoQuestions.AddQuestion("example.com", QclassIN, DNSRecordTypeA)
+
+	longLabel := query()
+	longLabel.Questions[0].Qname = strings.Repeat("a", 64) + ".org"
+
+	emptyLabel := query()
+	emptyLabel.Answers = []Answer{{Name: "www..example.org", Type: DNSRecordTypeA, Class: QclassIN}}
+	emptyLabel.updateCounts()
+
+	badTarget := query()
+	badTarget.Answers = []Answer{{Name: "www.example.org", Type: DNSRecordTypeCNAME, Class: QclassIN, RData: &RecordTypeCNAME{Target: "host..example.org"}}}
+	badTarget.updateCounts()
+
+	wrongRData := query()
+	wrongRData.Answers = []Answer{{Name: "example.org", Type: DNSRecordTypeA, Class: QclassIN, RData: &RecordTypeAAAA{Addr: netip.MustParseAddr("2001:db8::1")}}}
+	wrongRData.updateCounts()
+
+	//built with the Add methods, which keep the counts up to date
+	var built DNSPacket
+	built.AddQuestion("example.org", QclassIN, DNSRecordTypeA)
+	built.AddAnswer("example.org", QclassIN, DNSRecordTypeA, 60, 4, []byte{192, 0, 2, 1})
+	built.AddAuthority("example.org", QclassIN, DNSRecordTypeNS, 60, 0, nil)
+	built.Authority[0].RData = &RecordTypeNS{Host: "ns.example.org"}
+	built.AddAdditional("ns.example.org", QclassIN, DNSRecordTypeA, 60, 4, []byte{192, 0, 2, 53})
+
+	//a record appended without updating the header counts
+	extraAnswer := query()
+	extraAnswer.Answers = append(extraAnswer.Answers, Answer{Name: "example.org", Type: DNSRecordTypeA, Class: QclassIN, Data: []byte{192, 0, 2, 1}})
+
+	//Arcount has to count the OPT record
+	optNotCounted := query()
+	optNotCounted.EDNS = NewOPT(DefaultUDPSize)
+
+	withOPT := query()
+	withOPT.EDNS = NewOPT(DefaultUDPSize)
+	withOPT.updateCounts()
+
+	noQuestion := DNSPacket{Header: Header{ID: 1, QR: true}}
+
+	refused := DNSPacket{Header: Header{ID: 1, QR: true, Rcode: RcodeRefused}}
+
+	notify := DNSPacket{Header: Header{ID: 1, Opcode: OpcodeNotify}}
+	notify.AddQuestion("example.org", QclassIN, DNSRecordTypeSOA)
+	notify.AddQuestion("example.com", QclassIN, DNSRecordTypeSOA)
+
+	tables := []struct {
+		name   string
+		packet DNSPacket
+		err    error
+		encode bool
+	}{
+		{"valid query", valid, nil, true},
+		{"opcode out of range", badOpcode, ErrBadOpcode, false},
+		{"rcode needs EDNS", badRcode, ErrBadRcode, false},
+		{"Z bit set", reserved, ErrReservedBit, true},
+		{"two questions", twoQuestions, ErrTooManyQuestions, true},
+		{"label too long", longLabel, ErrLabelTooLong, false},
+		{"empty label in owner", emptyLabel, ErrEmptyLabel, false},
+		{"empty label in rdata", badTarget, ErrEmptyLabel, false},
+		{"AAAA rdata in an A record", wrongRData, ErrBadRdata, false},
+		{"built with the Add methods", built, nil, true},
+		{"answer not counted", extraAnswer, ErrCountMismatch, true},
+		{"OPT not counted", optNotCounted, ErrCountMismatch, true},
+		{"OPT counted", withOPT, nil, true},
+		{"response without question", noQuestion, ErrMissingQuestion, true},
+		{"refused without question", refused, nil, true},
+		{"notify with two questions", notify, nil, true},
+	}
+
+	for _, table := range tables {
+		err := table.packet.Validate()
+		if !errors.Is(err, table.err) || (err == nil) != (table.err == nil) {
+			t.Errorf("%s: Fail\nGot: %v\nWant: %v", table.name, err, table.err)
+		}
+
+		//Encode only rejects what it cannot write faithfully
+		_, err = Encode(&table.packet)
+		if (err == nil) != table.encode {
+			t.Errorf("%s: Fail\nGot encode error: %v", table.name, err)
+		}
+	}
+}
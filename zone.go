@@ -0,0 +1,337 @@
+package dnsPacket
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//Master files (RFC 1035 5)
+//
+//A zone file holds one record per line, in presentation format (see
+//ParseRR), and these directives:
+//
+//  $ORIGIN <name>                the origin relative names are completed with
+//  $TTL <ttl>                    the TTL of records without one (RFC 2308)
+//  $INCLUDE <file> [<origin>]    reads another file in place
+//  $GENERATE <range> <lhs> ...    writes a series of records (see generate.go)
+//
+//A record starting with white space has the owner of the record before
+//it. Records without a class have the class of the record before them
+
+//how many $INCLUDEs may be nested inside each other
+const maxIncludeDepth = 20
+
+//ZoneParser reads the records of a zone file one at a time, so zones
+//of any size can be read without holding them in memory.
+//Use it like a bufio.Scanner:
+//
+//  zp := NewZoneParser(f, "example.org.", "example.org.zone")
+//  for answer, ok := zp.Next(); ok; answer, ok = zp.Next() {
+//      ...
+//  }
+//  if err := zp.Err(); err != nil {
+//      ...
+//  }
+type ZoneParser struct {
+	files    []*zoneFile //the file being read is last, the files including it before
+	ttl      uint32      //$TTL or, until one is set, the TTL of the last record
+	hasTTL   bool
+	ttlIsSet bool //ttl comes from $TTL
+	owner    string
+	hasOwner bool
+	class    int
+	generate *generator //the $GENERATE being expanded
+	err      error
+}
+
+//a file being read and the origin in use inside it
+type zoneFile struct {
+	reader *bufio.Reader
+	closer io.Closer //nil for the reader passed to NewZoneParser
+	name   string
+	line   int
+	origin string
+}
+
+//NewZoneParser returns a parser reading the zone file in r. Relative
+//names are completed with origin until a $ORIGIN says otherwise. file is
+//used in errors and to find the files named by $INCLUDE, which are looked
+//up relative to the directory of the file including them
+func NewZoneParser(r io.Reader, origin string, file string) *ZoneParser {
+	zp := &ZoneParser{class: QclassIN}
+
+	origin, err := parseName(origin, ".")
+	if err != nil {
+		zp.err = &ParseError{File: file, Token: origin, Err: err}
+	}
+
+	zp.files = []*zoneFile{{reader: bufio.NewReader(r), name: file, origin: origin}}
+
+	return zp
+}
+
+//Next returns the next record of the zone. It returns false at the end of
+//the zone or on the first error, which Err returns
+func (zp *ZoneParser) Next() (Answer, bool) {
+	for zp.err == nil && len(zp.files) > 0 {
+		if zp.generate != nil {
+			answer, ok := zp.nextGenerated()
+			if ok {
+				return answer, true
+			}
+
+			continue
+		}
+
+		file := zp.files[len(zp.files)-1]
+
+		tokens, blankOwner, line, err := file.readRecord()
+		if err == io.EOF {
+			zp.closeFile()
+			continue
+		}
+
+		if err != nil {
+			zp.fail(file, line, "", err)
+			break
+		}
+
+		if len(tokens) == 0 {
+			continue
+		}
+
+		if !blankOwner && strings.HasPrefix(tokens[0].text, "$") && !tokens[0].quoted {
+			if err := zp.directive(file, line, tokens); err != nil {
+				zp.fail(file, line, tokens[0].text, err)
+			}
+
+			continue
+		}
+
+		answer, err := zp.record(file, tokens, blankOwner)
+		if err != nil {
+			zp.fail(file, line, "", err)
+			break
+		}
+
+		return answer, true
+	}
+
+	return Answer{}, false
+}
+
+//Err returns the first error met by Next, a *ParseError for malformed
+//zones. It is nil at the end of a zone read without problems
+func (zp *ZoneParser) Err() error {
+	return zp.err
+}
+
+//parses a record, inheriting the owner, TTL and class left out
+func (zp *ZoneParser) record(file *zoneFile, tokens []token, blankOwner bool) (Answer, error) {
+	if !blankOwner {
+		owner, err := parseName(tokens[0].text, file.origin)
+		if err != nil {
+			return Answer{}, &ParseError{Token: tokens[0].text, Err: err}
+		}
+
+		zp.owner, zp.hasOwner = owner, true
+		tokens = tokens[1:]
+	}
+
+	if !zp.hasOwner {
+		return Answer{}, &ParseError{Err: ErrNoOwner}
+	}
+
+	answer, hasTTL, err := parseRecord(zp.owner, zp.ttl, zp.class, tokens, file.origin)
+	if err != nil {
+		return Answer{}, err
+	}
+
+	if !hasTTL && !zp.hasTTL {
+		return Answer{}, &ParseError{Err: ErrNoTTL}
+	}
+
+	//without $TTL records default to the TTL of the record before them
+	if !zp.ttlIsSet {
+		zp.ttl, zp.hasTTL = answer.TTL, true
+	}
+
+	zp.class = answer.Class
+
+	return answer, nil
+}
+
+//returns the next record of the $GENERATE being expanded, or false
+//once it is done or fails
+func (zp *ZoneParser) nextGenerated() (Answer, bool) {
+	g := zp.generate
+
+	tokens, ok, err := g.next()
+	if err == nil && ok {
+		var answer Answer
+		if answer, err = zp.record(g.file, tokens, false); err == nil {
+			return answer, true
+		}
+	}
+
+	if err != nil {
+		zp.fail(g.file, g.line, "", err)
+	}
+
+	zp.generate = nil
+
+	return Answer{}, false
+}
+
+//handles a $ directive found on line
+func (zp *ZoneParser) directive(file *zoneFile, line int, tokens []token) error {
+	args := tokens[1:]
+
+	switch strings.ToUpper(tokens[0].text) {
+	case "$ORIGIN":
+		if len(args) != 1 {
+			return ErrBadDirective
+		}
+
+		origin, err := parseName(args[0].text, file.origin)
+		if err != nil {
+			return err
+		}
+
+		file.origin = origin
+
+	case "$TTL":
+		if len(args) != 1 {
+			return ErrBadDirective
+		}
+
+		ttl, err := parseTTL(args[0].text)
+		if err != nil {
+			return err
+		}
+
+		zp.ttl, zp.hasTTL, zp.ttlIsSet = ttl, true, true
+
+	case "$INCLUDE":
+		if len(args) != 1 && len(args) != 2 {
+			return ErrBadDirective
+		}
+
+		return zp.include(file, args)
+
+	case "$GENERATE":
+		g, err := newGenerator(file, line, args)
+		if err != nil {
+			return err
+		}
+
+		zp.generate = g
+
+	default:
+		return ErrBadDirective
+	}
+
+	return nil
+}
+
+//starts reading the file named by $INCLUDE. Its origin, given or
+//inherited, does not leak back into the including file
+func (zp *ZoneParser) include(file *zoneFile, args []token) error {
+	if len(zp.files) > maxIncludeDepth {
+		return ErrIncludeDepth
+	}
+
+	origin := file.origin
+	if len(args) == 2 {
+		var err error
+		if origin, err = parseName(args[1].text, file.origin); err != nil {
+			return err
+		}
+	}
+
+	name := args[0].text
+	if !filepath.IsAbs(name) && file.name != "" {
+		name = filepath.Join(filepath.Dir(file.name), name)
+	}
+
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+
+	zp.files = append(zp.files, &zoneFile{reader: bufio.NewReader(f), closer: f, name: name, origin: origin})
+
+	return nil
+}
+
+//stops reading the current file and goes back to the one including it
+func (zp *ZoneParser) closeFile() {
+	file := zp.files[len(zp.files)-1]
+	zp.files = zp.files[:len(zp.files)-1]
+
+	if file.closer != nil {
+		file.closer.Close()
+	}
+}
+
+//records err, with the file and line it was met on, and closes every file
+func (zp *ZoneParser) fail(file *zoneFile, line int, token string, err error) {
+	var parseErr *ParseError
+	if !errors.As(err, &parseErr) {
+		parseErr = &ParseError{Token: token, Err: err}
+	}
+
+	parseErr.File, parseErr.Line = file.name, line
+	zp.err = parseErr
+
+	for len(zp.files) > 0 {
+		zp.closeFile()
+	}
+}
+
+//reads the lines making up the next record, more than one when
+//parentheses are open. blankOwner is true if the record starts with
+//white space, line is the line it starts on. io.EOF is returned once
+//the file has no records left
+func (file *zoneFile) readRecord() (tokens []token, blankOwner bool, line int, err error) {
+	depth := 0
+	tokens = make([]token, 0)
+
+	for first := true; ; first = false {
+		text, readErr := file.reader.ReadString('\n')
+		if readErr != nil && readErr != io.EOF {
+			return nil, false, file.line, readErr
+		}
+
+		if text == "" && readErr == io.EOF {
+			if depth > 0 {
+				return nil, false, line, ErrSyntax
+			}
+
+			return nil, false, file.line, io.EOF
+		}
+
+		file.line++
+
+		if first {
+			line = file.line
+			blankOwner = text[0] == ' ' || text[0] == '\t'
+		}
+
+		lineTokens, lineDepth, scanErr := scanTokens(text, depth)
+		if scanErr != nil {
+			return nil, false, line, scanErr
+		}
+
+		tokens = append(tokens, lineTokens...)
+		depth = lineDepth
+
+		if depth == 0 {
+			return tokens, blankOwner, line, nil
+		}
+	}
+}
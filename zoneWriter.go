@@ -0,0 +1,103 @@
+package dnsPacket
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+)
+
+//a record with what it is sorted by
+type zoneEntry struct {
+	answer Answer
+	owner  Name
+	rdata  []byte //the uncompressed wire form of the RDATA
+}
+
+//WriteZone writes records as a zone file that NewZoneParser reads back.
+//The records are written in canonical order: SOA records first, then by
+//owner name as RFC 4034 6.1 orders names, by type and by RDATA. Owner
+//names under origin are written relative to it, after a $ORIGIN line,
+//and the columns are aligned with tabs
+func WriteZone(w io.Writer, records []Answer, origin string) error {
+	originName, err := NewName(origin)
+	if err != nil {
+		return fmt.Errorf("dnsPacket: origin %q: %w", origin, err)
+	}
+
+	entries := make([]zoneEntry, len(records))
+	for i, a := range records {
+		entries[i].answer = a
+
+		if entries[i].owner, err = NewName(a.Name); err != nil {
+			return fmt.Errorf("dnsPacket: owner name %q: %w", a.Name, err)
+		}
+
+		entries[i].rdata = a.Data
+		if a.RData != nil {
+			if entries[i].rdata, err = a.RData.Encode(make([]byte, 0), nil); err != nil {
+				return fmt.Errorf("dnsPacket: %s: %w", a.Name, err)
+			}
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return compareEntries(entries[i], entries[j]) < 0
+	})
+
+	tw := tabwriter.NewWriter(w, 0, 8, 1, '\t', 0)
+
+	if !originName.IsRoot() {
+		fmt.Fprintf(tw, "$ORIGIN %s\n", originName)
+	}
+
+	for _, entry := range entries {
+		a := entry.answer
+		owner := relativeName(entry.owner, originName)
+
+		fmt.Fprintf(tw, "%s\t%d\t%s\t%s\t%s\n", owner, a.TTL, ClassToString(a.Class), TypeToString(a.Type), a.rdataString())
+	}
+
+	return tw.Flush()
+}
+
+//orders SOA records first, then by owner, type and RDATA
+func compareEntries(a zoneEntry, b zoneEntry) int {
+	aSOA, bSOA := a.answer.Type == DNSRecordTypeSOA, b.answer.Type == DNSRecordTypeSOA
+	if aSOA != bSOA {
+		if aSOA {
+			return -1
+		}
+
+		return 1
+	}
+
+	if c := a.owner.Compare(b.owner); c != 0 {
+		return c
+	}
+
+	if a.answer.Type != b.answer.Type {
+		if a.answer.Type < b.answer.Type {
+			return -1
+		}
+
+		return 1
+	}
+
+	return bytes.Compare(a.rdata, b.rdata)
+}
+
+//returns name relative to origin, "@" for origin itself, or fully
+//qualified when it is not below origin
+func relativeName(name Name, origin Name) string {
+	if origin.IsRoot() || !name.IsSubdomainOf(origin) {
+		return name.String()
+	}
+
+	if len(name.labels) == len(origin.labels) {
+		return "@"
+	}
+
+	return joinLabels(name.labels[:len(name.labels)-len(origin.labels)])
+}
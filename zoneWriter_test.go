@@ -0,0 +1,90 @@
+package dnsPacket
+
+import (
+	"bytes"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestWriteZone(t *testing.T) {
+	zone := "$TTL 1h\n" +
+		"www CNAME @\n" +
+		"b.c.example.org. A 192.0.2.3\n" +
+		"@ MX 20 mail\n" +
+		"@ MX 10 mail\n" +
+		"A A 192.0.2.1\n" +
+		"z.example.com. A 192.0.2.9\n" +
+		"@ SOA ns hostmaster 1 2 3 4 5\n" +
+		"c A 192.0.2.2\n"
+
+	records := make([]Answer, 0)
+	zp := NewZoneParser(strings.NewReader(zone), "example.org.", "inline")
+	for answer, ok := zp.Next(); ok; answer, ok = zp.Next() {
+		records = append(records, answer)
+	}
+
+	if err := zp.Err(); err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := WriteZone(buf, records, "example.org."); err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
+	want := "$ORIGIN example.org.\n" +
+		"@\t\t3600\tIN\tSOA\tns.example.org. hostmaster.example.org. 1 2 3 4 5\n" +
+		"z.example.com.\t3600\tIN\tA\t192.0.2.9\n" +
+		"@\t\t3600\tIN\tMX\t10 mail.example.org.\n" +
+		"@\t\t3600\tIN\tMX\t20 mail.example.org.\n" +
+		"A\t\t3600\tIN\tA\t192.0.2.1\n" +
+		"c\t\t3600\tIN\tA\t192.0.2.2\n" +
+		"b.c\t\t3600\tIN\tA\t192.0.2.3\n" +
+		"www\t\t3600\tIN\tCNAME\texample.org.\n"
+
+	if buf.String() != want {
+		t.Errorf("Fail\nGot:\n%s\nWant:\n%s", buf.String(), want)
+	}
+}
+
+func TestWriteZoneRoundTrip(t *testing.T) {
+	f, err := os.Open("testdata/zone/example.org.zone")
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+	defer f.Close()
+
+	records := make([]Answer, 0)
+	zp := NewZoneParser(f, ".", "testdata/zone/example.org.zone")
+	for answer, ok := zp.Next(); ok; answer, ok = zp.Next() {
+		records = append(records, answer)
+	}
+
+	if err := zp.Err(); err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := WriteZone(buf, records, "example.org."); err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
+	written, err := readZone(NewZoneParser(buf, ".", "written"))
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
+	want := make([]string, len(records))
+	for i, answer := range records {
+		want[i] = answer.String()
+	}
+
+	sort.Strings(written)
+	sort.Strings(want)
+
+	if strings.Join(written, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Fail\nGot:\n%s\nWant:\n%s", strings.Join(written, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,151 @@
+package dnsPacket
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"strings"
+	"testing"
+)
+
+//reads every record of a zone
+func readZone(zp *ZoneParser) ([]string, error) {
+	records := make([]string, 0)
+
+	for answer, ok := zp.Next(); ok; answer, ok = zp.Next() {
+		records = append(records, answer.String())
+	}
+
+	return records, zp.Err()
+}
+
+func TestZoneParser(t *testing.T) {
+	f, err := os.Open("testdata/zone/example.org.zone")
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+	defer f.Close()
+
+	records, err := readZone(NewZoneParser(f, ".", "testdata/zone/example.org.zone"))
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
+	want := []string{
+		"example.org.\t3600\tIN\tSOA\tns.example.org. hostmaster.example.org. 2024010101 7200 3600 1209600 300",
+		"example.org.\t3600\tIN\tNS\tns.example.org.",
+		"example.org.\t3600\tIN\tMX\t10 mail.example.org.",
+		"ns.example.org.\t3600\tIN\tA\t192.0.2.1",
+		"mail.example.org.\t300\tIN\tA\t192.0.2.2",
+		"mail.example.org.\t3600\tIN\tAAAA\t2001:db8::2",
+		"www.example.org.\t3600\tIN\tCNAME\texample.org.",
+		"txt.example.org.\t3600\tIN\tTXT\t\"hello world\" \"semi;colon\"",
+		"host1.sub.example.org.\t3600\tIN\tA\t192.0.2.10",
+		"host2.other.example.org.\t3600\tIN\tA\t192.0.2.11",
+		"after.example.org.\t3600\tIN\tA\t192.0.2.9",
+	}
+
+	if strings.Join(records, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Fail\nGot:\n%s\nWant:\n%s", strings.Join(records, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestZoneParserInheritance(t *testing.T) {
+	zone := "a 60 CH TXT one\n" +
+		"  TXT two\n" +
+		"b 1d IN A 192.0.2.1\n" +
+		"  A 192.0.2.2\n"
+
+	records, err := readZone(NewZoneParser(strings.NewReader(zone), "example.org", "inline"))
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
+	want := []string{
+		"a.example.org.\t60\tCH\tTXT\t\"one\"",
+		"a.example.org.\t60\tCH\tTXT\t\"two\"",
+		"b.example.org.\t86400\tIN\tA\t192.0.2.1",
+		"b.example.org.\t86400\tIN\tA\t192.0.2.2",
+	}
+
+	if strings.Join(records, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Fail\nGot:\n%s\nWant:\n%s", strings.Join(records, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestZoneParserGenerate(t *testing.T) {
+	zone := "$TTL 1h\n" +
+		"$GENERATE 1-3 host$ A 192.0.2.$\n" +
+		"$GENERATE 0-4/2 ${10,3,d} PTR host${0,2,x}.example.org.\n" +
+		"$GENERATE 255-256 ${0,4,X} TXT \"\\$$\"\n" +
+		"$GENERATE 1-1 ${0,7,n}.ip6 AAAA 2001:db8::${0,0,o}\n" +
+		"$GENERATE 0-1 ${0,4,n} PTR host$.example.org.\n"
+
+	records, err := readZone(NewZoneParser(strings.NewReader(zone), "example.org.", "inline"))
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+
+	want := []string{
+		"host1.example.org.\t3600\tIN\tA\t192.0.2.1",
+		"host2.example.org.\t3600\tIN\tA\t192.0.2.2",
+		"host3.example.org.\t3600\tIN\tA\t192.0.2.3",
+		"010.example.org.\t3600\tIN\tPTR\thost00.example.org.",
+		"012.example.org.\t3600\tIN\tPTR\thost02.example.org.",
+		"014.example.org.\t3600\tIN\tPTR\thost04.example.org.",
+		"00FF.example.org.\t3600\tIN\tTXT\t\"$255\"",
+		"0100.example.org.\t3600\tIN\tTXT\t\"$256\"",
+		"1.0.0.0.ip6.example.org.\t3600\tIN\tAAAA\t2001:db8::1",
+		//an even nibble width ends in a dot, the name is absolute as in BIND
+		"0.0.\t3600\tIN\tPTR\thost0.example.org.",
+		"1.0.\t3600\tIN\tPTR\thost1.example.org.",
+	}
+
+	if strings.Join(records, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Fail\nGot:\n%s\nWant:\n%s", strings.Join(records, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestZoneParserErrors(t *testing.T) {
+	tables := []struct {
+		zone string
+		line int
+		err  error
+	}{
+		{"a 60 A 192.0.2.1\n\nb 60 A bogus\n", 3, ErrBadRdata},
+		{" 60 A 192.0.2.1\n", 1, ErrNoOwner},
+		{"a A 192.0.2.1\n", 1, ErrNoTTL},
+		{"$TTL 1h\n$GENERATE\n", 2, ErrBadDirective},
+		{"$TTL 1h\n$GENERATE 3-1 host$ A 192.0.2.$\n", 2, ErrBadDirective},
+		{"$TTL 1h\n$GENERATE 1-3/0 host$ A 192.0.2.$\n", 2, ErrBadDirective},
+		{"$TTL 1h\n$GENERATE 1-3 ${0,3,q} A 192.0.2.$\n", 2, ErrBadDirective},
+		{"$TTL 1h\n$GENERATE 1-3 ${0,3 A 192.0.2.$\n", 2, ErrBadDirective},
+		{"$TTL 1h\n\n$GENERATE 250-260 host$ A 192.0.2.$\n", 3, ErrBadRdata},
+		{"$TTL 1h\n$TTL forever\n", 2, ErrBadTTL},
+		{"$TTL 1h\na SOA ns hostmaster (\n1 2 3 4 5\n", 2, ErrSyntax},
+		{"$TTL 1h\na TXT \"open\n", 2, ErrSyntax},
+		{"$INCLUDE testdata/zone/missing.zone\n", 1, fs.ErrNotExist},
+	}
+
+	for _, table := range tables {
+		_, err := readZone(NewZoneParser(strings.NewReader(table.zone), "example.org.", ""))
+
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) || !errors.Is(err, table.err) || parseErr.Line != table.line {
+			t.Errorf("Fail %q\nGot: %v\nWant: line %d %v", table.zone, err, table.line, table.err)
+		}
+	}
+}
+
+func TestZoneParserIncludeLoop(t *testing.T) {
+	f, err := os.Open("testdata/zone/loop.zone")
+	if err != nil {
+		t.Fatalf("Fail\nGot error: %s", err)
+	}
+	defer f.Close()
+
+	_, err = readZone(NewZoneParser(f, "example.org.", "testdata/zone/loop.zone"))
+	if !errors.Is(err, ErrIncludeDepth) {
+		t.Errorf("Fail\nGot: %v\nWant: %v", err, ErrIncludeDepth)
+	}
+}